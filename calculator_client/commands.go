@@ -0,0 +1,1146 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+type command struct {
+	name  string
+	usage string
+	help  string
+	run   func(cc *grpc.ClientConn, opts options, args []string) error
+}
+
+var commands = []command{
+	{"sum", "sum [-overflow mode] <a> <b>", "add two numbers (unary)", doSum},
+	{"eval", "eval [-session id] <expression>", "evaluate an expression such as \"(3 + 4) * sqrt(16) / 2^3\", or statements such as \"x = 5; x * 2\" in a session", doEvaluate},
+	{"function", "function define [-replace] <definition> | list | delete <name>", "define a function such as \"f(x, y) = x^2 + y\" for eval, list the functions or delete one", doFunction},
+	{"session", "session new | vars [id] | delete [id]", "create a session for eval, list its variables or delete it; id defaults to $CALC_SESSION or the REPL's session", doSession},
+	{"primes", "primes <n>", "decompose n into prime factors (server streaming)", doServerStreaming},
+	{"average", "average <n>...", "average of the numbers (client streaming)", doClientStreaming},
+	{"stats", "stats [-p list] [n...]", "count, sum, mean, min, max, variance, median and percentiles of the numbers, or of stdin (client streaming)", doStatistics},
+	{"max", "max <n>...", "running maximum of the numbers (bidi streaming)", doBiDiStreaming},
+	{"aggregate", "aggregate [-a list] [-window type] [-size n] [-every d] [-alpha a] [-on-change] [n...]", "running aggregates of the numbers, or of each line of stdin (bidi streaming)", doRunningAggregate},
+	{"sqrt", "sqrt <n>", "square root of n (error handling)", doSquareRoot},
+	{"sum-deadline", "sum-deadline [-timeout d] [-overflow mode] <a> <b>", "add two numbers on a slow server call (deadlines)", doSumWithDeadLine},
+	{"bigint", "bigint <op> <a> <b> [modulus]", "arbitrary-precision integers; op: add, sub, mul, div, mod, pow, modpow", doBigInt},
+	{"bigrat", "bigrat [-decimals n] <op> <a> <b>", "arbitrary-precision rationals such as -3/4 or 1.25; op: add, sub, mul, div, pow", doBigRat},
+	{"factor", "factor [-budget d] <n>", "prime factors of an integer of any size with exponents, within a work budget", doFactorize},
+	{"isprime", "isprime [-rounds n] <n>", "whether n is prime; exact up to 64 bits, probabilistic above", doIsPrime},
+	{"nextprime", "nextprime [-rounds n] <n>", "smallest prime above n", doNextPrime},
+	{"prevprime", "prevprime [-rounds n] <n>", "largest prime below n", doPrevPrime},
+	{"listprimes", "listprimes <from> <to>", "primes from from to to (server streaming)", doListPrimes},
+	{"health", "health [service]", "check the server's health (exit status 1 unless SERVING)", doHealthCheck},
+	{"repl", "repl", "interactive prompt with line editing and history on one connection; type :help in it", doRepl},
+}
+
+// commandTable returns commands. The repl command looks commands up itself,
+// so naming the table in its initializer would be an initialization cycle;
+// init sets it instead.
+var commandTable func() []command
+
+func init() {
+	commandTable = func() []command { return commands }
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commandTable() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// usageError reports that a command was called with bad arguments.
+type usageError struct {
+	error
+}
+
+func usageErrorf(format string, a ...interface{}) error {
+	return usageError{fmt.Errorf(format, a...)}
+}
+
+// parseFlags parses the flags of a command and returns its arguments. A
+// negative number ends the flags rather than being taken for one, so that
+// "sum -5 3" works.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	i := 0
+	for i < len(args) {
+		arg := args[i]
+		if arg == "--" {
+			i++ // fs.Parse drops it
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || isNumber(arg) {
+			break
+		}
+		i++
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := fs.Lookup(name); f != nil {
+			if b, ok := f.Value.(interface{ IsBoolFlag() bool }); !ok || !b.IsBoolFlag() {
+				i++ // the flag's value
+			}
+		}
+	}
+	if i > len(args) {
+		i = len(args)
+	}
+	if err := fs.Parse(args[:i]); err != nil {
+		return nil, usageError{err}
+	}
+	return append(fs.Args(), args[i:]...), nil
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// millis converts the value d of the duration flag name to whole milliseconds
+// for a uint32 field, rejecting durations that do not fit.
+func millis(name string, d time.Duration) (uint32, error) {
+	if d < 0 || d.Milliseconds() > math.MaxUint32 {
+		return 0, usageErrorf("invalid -%s %v: must be between 0 and %v", name, d, time.Duration(math.MaxUint32)*time.Millisecond)
+	}
+	return uint32(d.Milliseconds()), nil
+}
+
+// uint32Flag checks that the value v of the flag name fits a uint32 field.
+func uint32Flag(name string, v uint) (uint32, error) {
+	if v > math.MaxUint32 {
+		return 0, usageErrorf("invalid -%s %d: must be at most %d", name, v, uint32(math.MaxUint32))
+	}
+	return uint32(v), nil
+}
+
+// overflowFlag adds the -overflow flag of the sum commands to fs.
+func overflowFlag(fs *flag.FlagSet) *string {
+	return fs.String("overflow", "checked", "what to do if the sum does not fit in an int32: checked, saturating, wrapping or widened")
+}
+
+func parseOverflowMode(name string) (calculatorpb.OverflowMode, error) {
+	mode, ok := calculatorpb.OverflowMode_value["OVERFLOW_MODE_"+strings.ToUpper(name)]
+	if !ok {
+		return 0, usageErrorf("unknown overflow mode %q: must be checked, saturating, wrapping or widened", name)
+	}
+	return calculatorpb.OverflowMode(mode), nil
+}
+
+// printSum prints the result of a sum command, warning if it overflowed.
+func printSum(opts options, res protoadapt.MessageV1, mode calculatorpb.OverflowMode, sum int32, wide int64, overflowed bool) {
+	if mode == calculatorpb.OverflowMode_OVERFLOW_MODE_WIDENED {
+		printResult(opts, res, wide)
+	} else {
+		printResult(opts, res, sum)
+	}
+	if overflowed && opts.output == "text" {
+		fmt.Fprintln(os.Stderr, "calc: warning: the sum did not fit in an int32")
+	}
+}
+
+// bigOperations are the operation names of the bigint and bigrat commands.
+var bigOperations = map[string]calculatorpb.BigOperation{
+	"add":    calculatorpb.BigOperation_BIG_OPERATION_ADD,
+	"sub":    calculatorpb.BigOperation_BIG_OPERATION_SUBTRACT,
+	"mul":    calculatorpb.BigOperation_BIG_OPERATION_MULTIPLY,
+	"div":    calculatorpb.BigOperation_BIG_OPERATION_DIVIDE,
+	"mod":    calculatorpb.BigOperation_BIG_OPERATION_MODULO,
+	"pow":    calculatorpb.BigOperation_BIG_OPERATION_POWER,
+	"modpow": calculatorpb.BigOperation_BIG_OPERATION_MOD_POW,
+}
+
+func parseBigOperation(name string) (calculatorpb.BigOperation, error) {
+	op, ok := bigOperations[name]
+	if !ok {
+		return 0, usageErrorf("unknown operation %q", name)
+	}
+	return op, nil
+}
+
+func parseInt32s(args []string) ([]int32, error) {
+	numbers := make([]int32, 0, len(args))
+	for _, arg := range args {
+		n, err := strconv.ParseInt(arg, 10, 32)
+		if err != nil {
+			return nil, usageErrorf("invalid number %q: %v", arg, err)
+		}
+		numbers = append(numbers, int32(n))
+	}
+	return numbers, nil
+}
+
+// exactArgs parses args as exactly n int32 numbers.
+func exactArgs(args []string, n int) ([]int32, error) {
+	if len(args) != n {
+		return nil, usageErrorf("wrong number of arguments: want %d, got %d", n, len(args))
+	}
+	return parseInt32s(args)
+}
+
+// someArgs parses args as at least one int32 number.
+func someArgs(args []string) ([]int32, error) {
+	if len(args) == 0 {
+		return nil, usageErrorf("expected at least one number")
+	}
+	return parseInt32s(args)
+}
+
+func doSum(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	fs := flag.NewFlagSet("sum", flag.ContinueOnError)
+	overflow := overflowFlag(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	mode, err := parseOverflowMode(*overflow)
+	if err != nil {
+		return err
+	}
+
+	numbers, err := exactArgs(args, 2)
+	if err != nil {
+		return err
+	}
+
+	req := &calculatorpb.SumRequest{
+		FirstNumber:  numbers[0],
+		SecondUmber:  numbers[1],
+		OverflowMode: mode,
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	res, err := c.Sum(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	printSum(opts, res, mode, res.SumResult, res.WideSumResult, res.Overflowed)
+	return nil
+}
+
+func doEvaluate(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	session := fs.String("session", opts.session, "evaluate in this session (default $CALC_SESSION, or the REPL's session)")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usageErrorf("missing expression")
+	}
+	// The expression may be split over several arguments, as in calc eval 1 + 2.
+	req := &calculatorpb.EvaluateRequest{
+		Expression: strings.Join(args, " "),
+		SessionId:  *session,
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	res, err := c.Evaluate(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	printResult(opts, res, res.Result)
+	return nil
+}
+
+func doSession(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	if len(args) == 0 {
+		return usageErrorf("missing subcommand: want new, vars or delete")
+	}
+	if args[0] == "new" {
+		if len(args) != 1 {
+			return usageErrorf("wrong number of arguments for new: want 0, got %d", len(args)-1)
+		}
+		ctx, cancel := callContext(opts.timeout)
+		defer cancel()
+
+		res, err := c.CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+		if err != nil {
+			return err
+		}
+		// Only the ID goes to stdout, for export CALC_SESSION=$(calc session new).
+		printResult(opts, res, res.SessionId)
+		if opts.output != "json" {
+			fmt.Fprintf(os.Stderr, "calc: the session expires after %v unused\n", time.Duration(res.TtlSeconds)*time.Second)
+		}
+		return nil
+	}
+
+	if args[0] != "vars" && args[0] != "delete" {
+		return usageErrorf("unknown subcommand %q: want new, vars or delete", args[0])
+	}
+	if len(args) > 2 {
+		return usageErrorf("wrong number of arguments for %s: want at most 1, got %d", args[0], len(args)-1)
+	}
+	id := opts.session
+	if len(args) == 2 {
+		id = args[1]
+	}
+	if id == "" {
+		return usageErrorf("missing session ID and $CALC_SESSION is not set")
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	if args[0] == "delete" {
+		_, err := c.DeleteSession(ctx, &calculatorpb.DeleteSessionRequest{SessionId: id})
+		return err
+	}
+
+	res, err := c.ListVariables(ctx, &calculatorpb.ListVariablesRequest{SessionId: id})
+	if err != nil {
+		return err
+	}
+	if opts.output == "json" {
+		printResult(opts, res, "")
+		return nil
+	}
+	for _, v := range res.Variables {
+		fmt.Printf("%s = %v\n", v.Name, v.Value)
+	}
+	return nil
+}
+
+func doFunction(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	if len(args) == 0 {
+		return usageErrorf("missing subcommand: want define, list or delete")
+	}
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	switch args[0] {
+	case "define":
+		fs := flag.NewFlagSet("function define", flag.ContinueOnError)
+		replace := fs.Bool("replace", false, "change the function if it is already defined")
+		args, err := parseFlags(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return usageErrorf("missing definition")
+		}
+		// Like an expression, the definition may be split over several
+		// arguments.
+		res, err := c.DefineFunction(ctx, &calculatorpb.DefineFunctionRequest{
+			Definition: strings.Join(args, " "),
+			Replace:    *replace,
+		})
+		if err != nil {
+			return err
+		}
+		printResult(opts, res, res.Function.Definition)
+
+	case "list":
+		if len(args) != 1 {
+			return usageErrorf("wrong number of arguments for list: want 0, got %d", len(args)-1)
+		}
+		res, err := c.ListFunctions(ctx, &calculatorpb.ListFunctionsRequest{})
+		if err != nil {
+			return err
+		}
+		if opts.output == "json" {
+			printResult(opts, res, "")
+			return nil
+		}
+		for _, f := range res.Functions {
+			fmt.Println(f.Definition)
+		}
+
+	case "delete":
+		if len(args) != 2 {
+			return usageErrorf("wrong number of arguments for delete: want 1, got %d", len(args)-1)
+		}
+		_, err := c.DeleteFunction(ctx, &calculatorpb.DeleteFunctionRequest{Name: args[1]})
+		return err
+
+	default:
+		return usageErrorf("unknown subcommand %q: want define, list or delete", args[0])
+	}
+	return nil
+}
+
+func doServerStreaming(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	if len(args) != 1 {
+		return usageErrorf("wrong number of arguments: want 1, got %d", len(args))
+	}
+	number, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return usageErrorf("invalid number %q: %v", args[0], err)
+	}
+
+	req := &calculatorpb.PrimeNumberDecompositionRequest{
+		Number: number,
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	stream, err := c.PrimeNumberDecomposition(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		printResult(opts, res, res.PrimeFactor)
+	}
+}
+
+func doClientStreaming(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	numbers, err := someArgs(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	stream, err := c.ComputeAverage(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, number := range numbers {
+		err := stream.Send(&calculatorpb.ComputeAverageRequest{
+			Number: number,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	printResult(opts, res, res.GetAverage())
+	return nil
+}
+
+// statisticsBatch is how many numbers the stats command sends per message.
+const statisticsBatch = 1024
+
+func doStatistics(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	list := fs.String("p", "", "comma-separated percentiles to compute, e.g. 90,99,99.9")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	var percentiles []float64
+	if *list != "" {
+		for _, field := range strings.Split(*list, ",") {
+			p, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return usageErrorf("invalid percentile %q: %v", field, err)
+			}
+			percentiles = append(percentiles, p)
+		}
+	}
+
+	// Without arguments the numbers are read from stdin.
+	var scanner *bufio.Scanner
+	if len(args) == 0 {
+		if opts.repl {
+			return usageErrorf("missing numbers: stdin cannot be read at the REPL")
+		}
+		scanner = bufio.NewScanner(os.Stdin)
+		scanner.Split(bufio.ScanWords)
+	}
+	next := func() (string, bool) {
+		if scanner != nil {
+			if !scanner.Scan() {
+				return "", false
+			}
+			return scanner.Text(), true
+		}
+		if len(args) == 0 {
+			return "", false
+		}
+		word := args[0]
+		args = args[1:]
+		return word, true
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	stream, err := c.ComputeStatistics(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &calculatorpb.ComputeStatisticsRequest{Percentiles: percentiles}
+	for {
+		word, ok := next()
+		if ok {
+			number, err := strconv.ParseFloat(word, 64)
+			if err != nil {
+				return usageErrorf("invalid number %q: %v", word, err)
+			}
+			req.Numbers = append(req.Numbers, number)
+			if len(req.Numbers) < statisticsBatch {
+				continue
+			}
+		}
+		if len(req.Numbers) > 0 || req.Percentiles != nil {
+			// On io.EOF the server has ended the call; CloseAndRecv
+			// returns its status.
+			if err := stream.Send(req); err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			req = &calculatorpb.ComputeStatisticsRequest{}
+		}
+		if !ok {
+			break
+		}
+	}
+	if scanner != nil && scanner.Err() != nil {
+		return fmt.Errorf("reading stdin: %v", scanner.Err())
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "count     %d\n", res.Count)
+	fmt.Fprintf(&text, "sum       %v\n", res.Sum)
+	fmt.Fprintf(&text, "mean      %v\n", res.Mean)
+	fmt.Fprintf(&text, "min       %v\n", res.Min)
+	fmt.Fprintf(&text, "max       %v\n", res.Max)
+	fmt.Fprintf(&text, "variance  %v\n", res.Variance)
+	fmt.Fprintf(&text, "stddev    %v\n", res.StandardDeviation)
+	fmt.Fprintf(&text, "median    %v", res.Median)
+	for _, p := range res.Percentiles {
+		fmt.Fprintf(&text, "\n%-9s %v", fmt.Sprintf("p%v", p.Percentile), p.Value)
+	}
+	printResult(opts, res, text.String())
+	if !res.Exact && opts.output == "text" {
+		fmt.Fprintf(os.Stderr, "calc: warning: median and percentiles are estimates within %v%%\n", res.RelativeError*100)
+	}
+	return nil
+}
+
+func doBiDiStreaming(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	numbers, err := someArgs(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	stream, err := c.FindMaximum(ctx)
+	if err != nil {
+		return err
+	}
+
+	sendErr := make(chan error, 1)
+
+	// send go routine
+	go func() {
+		for _, number := range numbers {
+			err := stream.Send(&calculatorpb.FindMaximumRequest{
+				Number: number,
+			})
+			if err != nil {
+				// The real error is reported by Recv.
+				sendErr <- nil
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	// receive on this go routine
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		printResult(opts, res, res.Maximum)
+	}
+
+	return <-sendErr
+}
+
+// windowTypes are the window names of the aggregate command.
+var windowTypes = map[string]calculatorpb.WindowType{
+	"cumulative": calculatorpb.WindowType_WINDOW_TYPE_CUMULATIVE,
+	"sliding":    calculatorpb.WindowType_WINDOW_TYPE_SLIDING_COUNT,
+	"tumbling":   calculatorpb.WindowType_WINDOW_TYPE_TUMBLING_TIME,
+}
+
+func doRunningAggregate(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	fs := flag.NewFlagSet("aggregate", flag.ContinueOnError)
+	list := fs.String("a", "min,max,mean", "comma-separated aggregates: count, sum, mean, min, max, ewma")
+	window := fs.String("window", "cumulative", "window type: cumulative, sliding (the last -size numbers) or tumbling (every -every)")
+	size := fs.Uint("size", 10, "numbers in a sliding window")
+	every := fs.Duration("every", 5*time.Second, "length of a tumbling window")
+	alpha := fs.Float64("alpha", 0.5, "smoothing factor of ewma")
+	onChange := fs.Bool("on-change", false, "only print updates that change a value")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	windowMs, err := millis("every", *every)
+	if err != nil {
+		return err
+	}
+	if windowMs == 0 {
+		return usageErrorf("invalid -every %v: must be at least 1ms", *every)
+	}
+	if *size > math.MaxUint32 {
+		return usageErrorf("invalid -size %d: must be at most %d", *size, uint32(math.MaxUint32))
+	}
+
+	req := &calculatorpb.RunningAggregateRequest{
+		WindowSize: uint32(*size),
+		WindowMs:   windowMs,
+		EwmaAlpha:  *alpha,
+	}
+	for _, name := range strings.Split(*list, ",") {
+		a, ok := calculatorpb.Aggregate_value["AGGREGATE_"+strings.ToUpper(strings.TrimSpace(name))]
+		if !ok || a == 0 {
+			return usageErrorf("unknown aggregate %q", name)
+		}
+		req.Aggregates = append(req.Aggregates, calculatorpb.Aggregate(a))
+	}
+	w, ok := windowTypes[*window]
+	if !ok {
+		return usageErrorf("unknown window type %q: want cumulative, sliding or tumbling", *window)
+	}
+	req.Window = w
+	if *onChange {
+		req.Emit = calculatorpb.EmitMode_EMIT_MODE_ON_CHANGE
+	}
+
+	// Each argument is a message; without arguments each line of stdin is.
+	var lines *bufio.Scanner
+	if len(args) == 0 {
+		if opts.repl {
+			return usageErrorf("missing numbers: stdin cannot be read at the REPL")
+		}
+		lines = bufio.NewScanner(os.Stdin)
+	}
+	next := func() ([]float64, bool, error) {
+		var words []string
+		if lines != nil {
+			if !lines.Scan() {
+				return nil, false, lines.Err()
+			}
+			words = strings.Fields(lines.Text())
+		} else {
+			if len(args) == 0 {
+				return nil, false, nil
+			}
+			words, args = args[:1], args[1:]
+		}
+		numbers := make([]float64, 0, len(words))
+		for _, word := range words {
+			number, err := strconv.ParseFloat(word, 64)
+			if err != nil {
+				return nil, false, usageErrorf("invalid number %q: %v", word, err)
+			}
+			numbers = append(numbers, number)
+		}
+		return numbers, true, nil
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	stream, err := c.RunningAggregate(ctx)
+	if err != nil {
+		return err
+	}
+
+	sendErr := make(chan error, 1)
+	go func() {
+		if err := stream.Send(req); err != nil {
+			sendErr <- nil // the real error is reported by Recv
+			return
+		}
+		for {
+			numbers, ok, err := next()
+			if err != nil {
+				sendErr <- err
+				cancel()
+				return
+			}
+			if !ok {
+				break
+			}
+			if err := stream.Send(&calculatorpb.RunningAggregateRequest{Numbers: numbers}); err != nil {
+				sendErr <- nil
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// A bad number cancels the call after reporting itself.
+			select {
+			case sendErr := <-sendErr:
+				if sendErr != nil {
+					return sendErr
+				}
+			default:
+			}
+			return err
+		}
+
+		var text strings.Builder
+		if res.WindowStartMs != 0 {
+			fmt.Fprintf(&text, "[%s] ", time.UnixMilli(res.WindowStartMs).Format("15:04:05.000"))
+		}
+		for i, v := range res.Values {
+			if i > 0 {
+				text.WriteString(" ")
+			}
+			fmt.Fprintf(&text, "%s=%v", strings.ToLower(strings.TrimPrefix(v.Aggregate.String(), "AGGREGATE_")), v.Value)
+		}
+		if res.WindowClosed {
+			text.WriteString(" (window closed)")
+		}
+		printResult(opts, res, text.String())
+	}
+
+	return <-sendErr
+}
+
+func doSquareRoot(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	numbers, err := exactArgs(args, 1)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	res, err := c.SquareRoot(ctx, &calculatorpb.SquareRootRequest{Number: numbers[0]})
+	if err != nil {
+		return err
+	}
+
+	printResult(opts, res, res.NumberRoot)
+	return nil
+}
+
+func doSumWithDeadLine(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	fs := flag.NewFlagSet("sum-deadline", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 5*time.Second, "deadline for the call")
+	overflow := overflowFlag(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	mode, err := parseOverflowMode(*overflow)
+	if err != nil {
+		return err
+	}
+
+	numbers, err := exactArgs(args, 2)
+	if err != nil {
+		return err
+	}
+
+	req := &calculatorpb.SumWithDeadLineRequest{
+		FirstNumber:  numbers[0],
+		SecondUmber:  numbers[1],
+		OverflowMode: mode,
+	}
+
+	ctx, cancel := callContext(*timeout)
+	defer cancel()
+
+	res, err := c.SumWithDeadLine(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	printSum(opts, res, mode, res.SumResult, res.WideSumResult, res.Overflowed)
+	return nil
+}
+
+func doBigInt(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	if len(args) < 1 {
+		return usageErrorf("missing operation")
+	}
+	op, err := parseBigOperation(args[0])
+	if err != nil {
+		return err
+	}
+	want := 3
+	if op == calculatorpb.BigOperation_BIG_OPERATION_MOD_POW {
+		want = 4
+	}
+	if len(args) != want {
+		return usageErrorf("wrong number of arguments for %s: want %d, got %d", args[0], want-1, len(args)-1)
+	}
+
+	req := &calculatorpb.BigIntRequest{
+		Operation: op,
+		A:         args[1],
+		B:         args[2],
+	}
+	if want == 4 {
+		req.Modulus = args[3]
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	res, err := c.BigIntArithmetic(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	printResult(opts, res, res.Result)
+	return nil
+}
+
+func doBigRat(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	fs := flag.NewFlagSet("bigrat", flag.ContinueOnError)
+	decimals := fs.Uint("decimals", 0, "also print the result as a decimal rounded to this many places")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 3 {
+		return usageErrorf("wrong number of arguments: want 3, got %d", len(args))
+	}
+	op, err := parseBigOperation(args[0])
+	if err != nil {
+		return err
+	}
+
+	places, err := uint32Flag("decimals", *decimals)
+	if err != nil {
+		return err
+	}
+
+	req := &calculatorpb.BigRatRequest{
+		Operation:     op,
+		A:             args[1],
+		B:             args[2],
+		DecimalPlaces: places,
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	res, err := c.BigRatArithmetic(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	text := res.Result
+	if res.Decimal != "" {
+		text += " = " + res.Decimal
+	}
+	printResult(opts, res, text)
+	return nil
+}
+
+func doFactorize(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	fs := flag.NewFlagSet("factor", flag.ContinueOnError)
+	budget := fs.Duration("budget", 0, "give up after this much work and print the parts left (0 means the server's budget)")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usageErrorf("wrong number of arguments: want 1, got %d", len(args))
+	}
+	budgetMs, err := millis("budget", *budget)
+	if err != nil {
+		return err
+	}
+
+	req := &calculatorpb.FactorizeRequest{
+		Number:   args[0],
+		BudgetMs: budgetMs,
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	stream, err := c.Factorize(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case opts.output == "json":
+			printResult(opts, res, "")
+		case res.Prime != nil:
+			fmt.Println(formatPower(res.Prime))
+		case res.Done:
+			if res.Incomplete {
+				for _, part := range res.Unfactored {
+					fmt.Printf("%s (not factored)\n", formatPower(part))
+				}
+				fmt.Fprintf(os.Stderr, "calc: warning: the work budget ran out after %d steps, %d digits are not factored\n", res.Steps, res.RemainingDigits)
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "calc: %d steps, %d digits left\n", res.Steps, res.RemainingDigits)
+		}
+	}
+}
+
+func doIsPrime(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	fs := flag.NewFlagSet("isprime", flag.ContinueOnError)
+	rounds := roundsFlag(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usageErrorf("wrong number of arguments: want 1, got %d", len(args))
+	}
+
+	r, err := uint32Flag("rounds", *rounds)
+	if err != nil {
+		return err
+	}
+
+	req := &calculatorpb.IsPrimeRequest{
+		Number: args[0],
+		Rounds: r,
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	res, err := c.IsPrime(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !res.Prime:
+		printResult(opts, res, "composite")
+	case res.Certain:
+		printResult(opts, res, "prime")
+	default:
+		printResult(opts, res, fmt.Sprintf("probably prime (error probability at most %.3g)", res.ErrorProbability))
+	}
+	return nil
+}
+
+func doNextPrime(cc *grpc.ClientConn, opts options, args []string) error {
+	return searchPrime(opts, "nextprime", args, calculatorpb.NewCalculatorServiceClient(cc).NextPrime)
+}
+
+func doPrevPrime(cc *grpc.ClientConn, opts options, args []string) error {
+	return searchPrime(opts, "prevprime", args, calculatorpb.NewCalculatorServiceClient(cc).PrevPrime)
+}
+
+// searchPrime runs the nextprime or prevprime command, which call the RPC
+// search.
+func searchPrime(opts options, name string, args []string,
+	search func(context.Context, *calculatorpb.NextPrimeRequest, ...grpc.CallOption) (*calculatorpb.NextPrimeResponse, error)) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	rounds := roundsFlag(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usageErrorf("wrong number of arguments: want 1, got %d", len(args))
+	}
+
+	r, err := uint32Flag("rounds", *rounds)
+	if err != nil {
+		return err
+	}
+
+	req := &calculatorpb.NextPrimeRequest{
+		Number: args[0],
+		Rounds: r,
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	res, err := search(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	printResult(opts, res, res.Prime)
+	if !res.Certain && opts.output == "text" {
+		fmt.Fprintf(os.Stderr, "calc: warning: probable prime, error probability at most %.3g\n", res.ErrorProbability)
+	}
+	return nil
+}
+
+func roundsFlag(fs *flag.FlagSet) *uint {
+	return fs.Uint("rounds", 0, "Miller-Rabin rounds for numbers above 64 bits (0 means the server's default)")
+}
+
+func doListPrimes(cc *grpc.ClientConn, opts options, args []string) error {
+	c := calculatorpb.NewCalculatorServiceClient(cc)
+
+	if len(args) != 2 {
+		return usageErrorf("wrong number of arguments: want 2, got %d", len(args))
+	}
+	var bounds [2]uint64
+	for i, arg := range args {
+		n, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return usageErrorf("invalid number %q: %v", arg, err)
+		}
+		bounds[i] = n
+	}
+
+	req := &calculatorpb.ListPrimesRequest{
+		From: bounds[0],
+		To:   bounds[1],
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	stream, err := c.ListPrimes(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if opts.output == "json" {
+			out.Flush()
+			printResult(opts, res, "")
+			continue
+		}
+		for _, p := range res.Primes {
+			fmt.Fprintln(out, p)
+		}
+	}
+}
+
+// formatPower formats a factor as p or p^e.
+func formatPower(p *calculatorpb.FactorPower) string {
+	if p.Exponent == 1 {
+		return p.Factor
+	}
+	return fmt.Sprintf("%s^%d", p.Factor, p.Exponent)
+}
+
+func doHealthCheck(cc *grpc.ClientConn, opts options, args []string) error {
+	if len(args) > 1 {
+		return usageErrorf("wrong number of arguments: want at most 1, got %d", len(args))
+	}
+	req := &healthpb.HealthCheckRequest{}
+	if len(args) == 1 {
+		req.Service = args[0]
+	}
+
+	ctx, cancel := callContext(opts.timeout)
+	defer cancel()
+
+	res, err := healthpb.NewHealthClient(cc).Check(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	printResult(opts, res, res.Status)
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("server is %v", res.Status)
+	}
+	return nil
+}
@@ -2,219 +2,331 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
 	"fmt"
-	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
-	"io"
-	"log"
-	"time"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/protoadapt"
 )
 
+type options struct {
+	address    string
+	tls        bool
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+	timeout    time.Duration
+	// trace is the span exporter: none, stdout or otlp.
+	trace        string
+	otlpEndpoint string
+	apiKey       string
+	token        string
+	// insecureCredentials allows sending apiKey and token without TLS.
+	insecureCredentials bool
+	// output is text or json.
+	output string
+	// session is the default session of eval and session: $CALC_SESSION,
+	// or at the REPL the session it made.
+	session string
+	// repl is set for commands run at the REPL, which owns stdin.
+	repl bool
+}
+
 func main() {
-	fmt.Println("Client is running...")
+	os.Exit(run(os.Args[1:]))
+}
 
-	// SSL config
-	tls := false
-	opts := grpc.WithInsecure()
-	if tls {
-		certFile := "../ssl/ca.crt"
-		creds, sslErr := credentials.NewClientTLSFromFile(certFile, "api.example.com")
-		if sslErr != nil {
-			log.Fatalf("Error while loading CA trust certifiate: %v", sslErr)
+func run(args []string) int {
+	var opts options
+	fs := flag.NewFlagSet("calc", flag.ContinueOnError)
+	fs.StringVar(&opts.address, "address", "localhost:50051", "server address (host:port)")
+	fs.BoolVar(&opts.tls, "tls", false, "connect over TLS")
+	fs.StringVar(&opts.caFile, "ca-file", "../ssl/ca.crt", "CA certificate used to verify the server")
+	fs.StringVar(&opts.certFile, "cert", "", "client certificate for mutual TLS (e.g. ../ssl/client.crt)")
+	fs.StringVar(&opts.keyFile, "key", "", "client private key for mutual TLS (e.g. ../ssl/client.pem)")
+	fs.StringVar(&opts.serverName, "server-name", "api.example.com", "expected server name in the server certificate")
+	fs.DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout for each call (0 means no timeout)")
+	fs.StringVar(&opts.output, "output", "text", "output format: text, or json for one JSON object per response and google.rpc.Status errors")
+	fs.StringVar(&opts.apiKey, "api-key", os.Getenv("CALC_API_KEY"), "API key sent with every call (default $CALC_API_KEY)")
+	fs.StringVar(&opts.token, "token", os.Getenv("CALC_TOKEN"), "JWT bearer token sent with every call (default $CALC_TOKEN)")
+	fs.BoolVar(&opts.insecureCredentials, "insecure-credentials", false, "send -api-key and -token without -tls, in the clear (for a local server)")
+	fs.StringVar(&opts.trace, "trace", "none", "export trace spans: none, stdout or otlp")
+	fs.StringVar(&opts.otlpEndpoint, "otlp-endpoint", "localhost:4317", "OTLP/gRPC collector address for -trace otlp")
+	fs.Usage = func() { usage(fs) }
+	opts.session = os.Getenv("CALC_SESSION")
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
 		}
-		opts = grpc.WithTransportCredentials(creds)
+		return 2
 	}
-
-	cc, err := grpc.Dial("localhost:50051", opts)
-	if err != nil {
-		log.Fatalf("could not connect to server: %v", err)
+	if fs.NArg() == 0 {
+		usage(fs)
+		return 2
+	}
+	if opts.output != "text" && opts.output != "json" {
+		fmt.Fprintf(os.Stderr, "calc: unknown output format %q: must be text or json\n", opts.output)
+		return 2
 	}
-	defer cc.Close()
 
-	c := calculatorpb.NewCalculatorServiceClient(cc)
+	cmd, ok := findCommand(fs.Arg(0))
+	if !ok {
+		fmt.Fprintf(os.Stderr, "calc: unknown command %q\n\n", fs.Arg(0))
+		usage(fs)
+		return 2
+	}
 
-	//doSum(c)
+	shutdownTracing, err := setupTracing(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "calc: setting up tracing: %v\n", err)
+		return 2
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "calc: flushing traces: %v\n", err)
+		}
+	}()
 
-	//doServerStreaming(c)
+	cc, err := dial(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "calc: could not connect to server: %v\n", err)
+		return 1
+	}
+	defer cc.Close()
 
-	//doClientStreaming(c)
+	if err := cmd.run(cc, opts, fs.Args()[1:]); err != nil {
+		if _, ok := err.(usageError); ok {
+			fmt.Fprintf(os.Stderr, "calc: %v\nusage: calc %s\n", err, cmd.usage)
+			return 2
+		}
+		printError(opts, err)
+		return 1
+	}
+	return 0
+}
 
-	//doBiDiStreaming(c)
+func dial(opts options) (*grpc.ClientConn, error) {
+	// SSL config
+	creds := grpc.WithInsecure()
+	if opts.tls {
+		tlsConfig, sslErr := clientTLSConfig(opts)
+		if sslErr != nil {
+			return nil, sslErr
+		}
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
 
-	// Correct call
-	//doSquareRoot(c, 10)
-	// Error call
-	//doSquareRoot(c, -3)
+	dialOpts := []grpc.DialOption{
+		creds,
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+		)),
+	}
+	if callCreds := newCallCredentials(opts); len(callCreds.headers) > 0 {
+		if callCreds.RequireTransportSecurity() && !opts.tls {
+			return nil, fmt.Errorf("credentials are only sent over TLS: add -tls, or -insecure-credentials for a local server")
+		}
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(callCreds))
+	}
 
-	doSumWithDeadLine(c, 5*time.Second)
-	doSumWithDeadLine(c, 3*time.Second)
+	return grpc.Dial(opts.address, dialOpts...)
 }
 
-func doSum(c calculatorpb.CalculatorServiceClient) {
-	fmt.Println("Starting to do a sum Unary RPC")
+// callCredentials are the request headers that authenticate every call.
+type callCredentials struct {
+	headers map[string]string
+	// insecure allows sending the headers over plaintext, for a local
+	// development server.
+	insecure bool
+}
 
-	req := &calculatorpb.SumRequest{
-		FirstNumber: 40,
-		SecondUmber: 2,
+func newCallCredentials(opts options) callCredentials {
+	creds := callCredentials{headers: map[string]string{}, insecure: opts.insecureCredentials}
+	if opts.apiKey != "" {
+		creds.headers["x-api-key"] = opts.apiKey
 	}
-
-	res, err := c.Sum(context.Background(), req)
-	if err != nil {
-		log.Fatalf("Error while calling sum RPC: %v", err)
+	if opts.token != "" {
+		creds.headers["authorization"] = "Bearer " + opts.token
 	}
+	return creds
+}
 
-	log.Printf("Response from server: %v", res.SumResult)
+func (c callCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return c.headers, nil
 }
 
-func doServerStreaming(c calculatorpb.CalculatorServiceClient) {
-	fmt.Println("Starting to do a PrimeDecomposition server streaming RPC")
+// RequireTransportSecurity keeps credentials off plaintext connections unless
+// -insecure-credentials allows it.
+func (c callCredentials) RequireTransportSecurity() bool {
+	return !c.insecure
+}
 
-	req := &calculatorpb.PrimeNumberDecompositionRequest{
-		Number: 12,
+// clientTLSConfig trusts the CA in opts.caFile and, for mutual TLS, presents
+// the client certificate in opts.certFile.
+func clientTLSConfig(opts options) (*tls.Config, error) {
+	pem, err := os.ReadFile(opts.caFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading CA trust certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("loading CA trust certificate: no certificates found in %s", opts.caFile)
 	}
 
-	stream, err := c.PrimeNumberDecomposition(context.Background(), req)
-	if err != nil {
-		log.Fatalf("Error while calling PrimeDecomposition RPC: %v", err)
+	tlsConfig := &tls.Config{
+		RootCAs:    pool,
+		ServerName: opts.serverName,
 	}
 
-	for {
-		res, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
+	if opts.certFile != "" || opts.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.certFile, opts.keyFile)
 		if err != nil {
-			log.Printf("Error while streaming PrimeDecomposition RPC: %v", err)
+			return nil, fmt.Errorf("loading client certificate: %v", err)
 		}
-		fmt.Println(res.PrimeFactor)
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
+
+	return tlsConfig, nil
 }
 
-func doClientStreaming(c calculatorpb.CalculatorServiceClient) {
-	fmt.Println("Starting to do a ComputeAverage client streaming RPC")
+// baseContext is the parent of the context of every call. The REPL replaces
+// it while a command runs so that Ctrl-C cancels the command's calls.
+var baseContext = context.Background()
 
-	stream, err := c.ComputeAverage(context.Background())
-	if err != nil {
-		log.Fatalf("Error while calling stream RPC: %v", err)
+// callContext returns the context for a single call, bounded by timeout
+// unless it is zero.
+func callContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(baseContext)
 	}
+	return context.WithTimeout(baseContext, timeout)
+}
 
-	numbers := []int32{2, 5, 7, 9, 12, 57}
-
-	for _, number := range numbers {
-		fmt.Printf("Sending number: %v\n", number)
-		err := stream.Send(&calculatorpb.ComputeAverageRequest{
-			Number: number,
-		})
+// printResult prints a response: text in text output, or res as one line of
+// JSON.
+func printResult(opts options, res protoadapt.MessageV1, text interface{}) {
+	if opts.output == "json" {
+		b, err := protojson.Marshal(protoadapt.MessageV2Of(res))
 		if err != nil {
-			log.Fatalf("Error while sending stream: %v", err)
+			fmt.Fprintf(os.Stderr, "calc: encoding response: %v\n", err)
+			return
 		}
+		fmt.Println(string(b))
+		return
 	}
-
-	res, err := stream.CloseAndRecv()
-	if err != nil {
-		log.Fatalf("Error while receiving response: %v", err)
-	}
-
-	fmt.Printf("The average is: %v\n", res.GetAverage())
+	fmt.Println(text)
 }
 
-func doBiDiStreaming(c calculatorpb.CalculatorServiceClient) {
-	fmt.Println("Starting to do a FindMaximum BiDi streaming RPC")
-
-	stream, err := c.FindMaximum(context.Background())
-	if err != nil {
-		log.Fatalf("Error while calling stream RPC: %v", err)
+// printError prints err and, for a status error, the details the server
+// attached to it. In json output a status error is printed as a
+// google.rpc.Status.
+func printError(opts options, err error) {
+	resError, ok := status.FromError(err)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "calc: %v\n", err)
+		return
 	}
 
-	waitingForChannel := make(chan struct{})
-
-	// send go routine
-	go func() {
-		numbers := []int32{2, 8, 1, 5, 37, 28, 42}
-
-		for _, number := range numbers {
-			err := stream.Send(&calculatorpb.FindMaximumRequest{
-				Number: number,
-			})
-			if err != nil {
-				log.Fatalf("Error while sending stream: %v", err)
-			}
-			time.Sleep(1000 * time.Millisecond)
-		}
-
-		err := stream.CloseSend()
-		if err != nil {
-			log.Fatalf("Error while closing stream: %v", err)
+	if opts.output == "json" {
+		b, jsonErr := protojson.Marshal(resError.Proto())
+		if jsonErr == nil {
+			fmt.Fprintln(os.Stderr, string(b))
+			return
 		}
-	}()
+	}
 
-	// receive go routine
-	go func() {
-		for {
-			res, err := stream.Recv()
-			if err == io.EOF {
-				break
+	message := resError.Message()
+	var lines, pointer []string
+	for _, detail := range resError.Details() {
+		switch d := detail.(type) {
+		case *errdetails.LocalizedMessage:
+			message = d.GetMessage()
+		case *errdetails.ErrorInfo:
+			line := fmt.Sprintf("reason: %s (%s)", d.GetReason(), d.GetDomain())
+			keys := make([]string, 0, len(d.GetMetadata()))
+			for k := range d.GetMetadata() {
+				keys = append(keys, k)
 			}
-			if err != nil {
-				log.Fatalf("Error while receving stream: %v", err)
-				break
+			sort.Strings(keys)
+			for _, k := range keys {
+				line += fmt.Sprintf(" %s=%s", k, d.GetMetadata()[k])
 			}
-
-			fmt.Printf("New maximun is: %v\n", res.Maximum)
-		}
-		close(waitingForChannel)
-	}()
-
-	<-waitingForChannel
-}
-
-func doSquareRoot(c calculatorpb.CalculatorServiceClient, number int32) {
-	fmt.Println("Starting to do a SquareRoot Unary RPC")
-
-	res, err := c.SquareRoot(context.Background(), &calculatorpb.SquareRootRequest{Number: number})
-	if err != nil {
-		resError, ok := status.FromError(err)
-		if ok {
-			// Actual error from gRPC (user error)
-			fmt.Printf("Error message from server: %v\n", resError.Message())
-			fmt.Println(resError.Code())
-			if resError.Code() == codes.InvalidArgument {
-				log.Fatalln("We probably sent a negative number")
+			lines = append(lines, line)
+			source := d.GetMetadata()["expression"]
+			if source == "" {
+				source = d.GetMetadata()["definition"]
+			}
+			pointer = pointAt(source, d.GetMetadata()["position"])
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				lines = append(lines, fmt.Sprintf("field %s: %s", v.GetField(), v.GetDescription()))
+			}
+		case *errdetails.QuotaFailure:
+			for _, v := range d.GetViolations() {
+				lines = append(lines, fmt.Sprintf("quota of %s exceeded: %s", v.GetSubject(), v.GetDescription()))
 			}
-		} else {
-			log.Fatalf("Big error calling SquareRoot: %v", err)
+		case *errdetails.RetryInfo:
+			lines = append(lines, fmt.Sprintf("retry after %v", d.GetRetryDelay().AsDuration().Round(time.Millisecond)))
+		case error:
+			lines = append(lines, fmt.Sprintf("undecodable detail: %v", d))
+		default:
+			lines = append(lines, fmt.Sprintf("%v", d))
 		}
 	}
-	fmt.Printf("Result of square root of %v: %v\n\n", number, res.NumberRoot)
-}
 
-func doSumWithDeadLine(c calculatorpb.CalculatorServiceClient, time time.Duration) {
-	fmt.Println("Starting to do a SumWithDeadLine RPC")
+	fmt.Fprintf(os.Stderr, "calc: %v: %v\n", resError.Code(), message)
+	for _, line := range append(lines, pointer...) {
+		fmt.Fprintf(os.Stderr, "  %s\n", line)
+	}
+}
 
-	req := &calculatorpb.SumWithDeadLineRequest{
-		FirstNumber: 40,
-		SecondUmber: 2,
+// pointAt returns expression with a caret under the character at position,
+// counted from 1, or nothing if either is missing.
+func pointAt(expression, position string) []string {
+	column, err := strconv.Atoi(position)
+	if expression == "" || err != nil || column < 1 {
+		return nil
 	}
+	return []string{expression, strings.Repeat(" ", column-1) + "^"}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time)
-	defer cancel()
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "Usage: calc [flags] <command> [arguments]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	printCommands(out)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Flags:")
+	fs.PrintDefaults()
+}
 
-	res, err := c.SumWithDeadLine(ctx, req)
-	if err != nil {
-		resError, ok := status.FromError(err)
-		if ok {
-			if resError.Code() == codes.DeadlineExceeded {
-				log.Fatalln("Timeout was hit! Deadline was exceeded")
-			} else {
-				log.Fatalf("Unexpected error: %v", err)
-			}
-		} else {
-			log.Fatalf("Error while calling sum RPC: %v", err)
+// printCommands prints the usage and help of every command in a table.
+func printCommands(out io.Writer) {
+	width := 0
+	for _, cmd := range commandTable() {
+		if len(cmd.usage) > width {
+			width = len(cmd.usage)
 		}
 	}
-
-	log.Printf("Response from server: %v\n\n", res.SumResult)
+	for _, cmd := range commandTable() {
+		fmt.Fprintf(out, "  %-*s  %s\n", width, cmd.usage, cmd.help)
+	}
 }
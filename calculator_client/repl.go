@@ -0,0 +1,372 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"github.com/peterh/liner"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// historyFile keeps the lines typed at the REPL, in the home directory.
+const historyFile = ".calc_history"
+
+// replSetting is a global flag that can be changed at the REPL with
+// :name value.
+type replSetting struct {
+	name  string
+	usage string
+	get   func(opts options) string
+	set   func(opts *options, value string) error
+	// redial means the connection must be made again for a change to apply.
+	redial bool
+}
+
+var replSettings = []replSetting{
+	{"address", ":address <host:port>", func(opts options) string { return opts.address }, func(opts *options, value string) error {
+		opts.address = value
+		return nil
+	}, true},
+	{"tls", ":tls on|off", func(opts options) string { return onOff(opts.tls) }, func(opts *options, value string) error {
+		on, err := parseOnOff(value)
+		opts.tls = on
+		return err
+	}, true},
+	{"timeout", ":timeout <duration>", func(opts options) string { return opts.timeout.String() }, func(opts *options, value string) error {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		opts.timeout = timeout
+		return nil
+	}, false},
+	{"output", ":output text|json", func(opts options) string { return opts.output }, func(opts *options, value string) error {
+		if value != "text" && value != "json" {
+			return fmt.Errorf("unknown output format %q: must be text or json", value)
+		}
+		opts.output = value
+		return nil
+	}, false},
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+func parseOnOff(value string) (bool, error) {
+	switch value {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q: want on or off", value)
+	}
+	return b, nil
+}
+
+// errQuit ends the REPL.
+var errQuit = errors.New("quit")
+
+// repl reads commands and expressions and runs them on one connection, which
+// is only made again when a setting such as :tls needs it. Expressions are
+// evaluated in opts.session, so variables and ans carry over between lines.
+type repl struct {
+	cc   *grpc.ClientConn
+	opts options
+	line *liner.State
+	// ownSession means the REPL created opts.session and deletes it when it
+	// ends, rather than using $CALC_SESSION.
+	ownSession bool
+}
+
+func doRepl(cc *grpc.ClientConn, opts options, args []string) error {
+	if len(args) != 0 {
+		return usageErrorf("wrong number of arguments: want 0, got %d", len(args))
+	}
+
+	opts.repl = true
+	r := &repl{cc: cc, opts: opts, line: liner.NewLiner()}
+	defer r.line.Close()
+	defer func() {
+		if r.cc != cc {
+			r.cc.Close()
+		}
+	}()
+	if r.opts.session == "" {
+		r.newSession()
+	}
+	defer r.endSession()
+	r.line.SetCtrlCAborts(true)
+	r.line.SetCompleter(r.complete)
+
+	if home, err := os.UserHomeDir(); err == nil {
+		history := filepath.Join(home, historyFile)
+		if f, err := os.Open(history); err == nil {
+			r.line.ReadHistory(f)
+			f.Close()
+		}
+		defer func() {
+			f, err := os.OpenFile(history, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "calc: saving history: %v\n", err)
+				return
+			}
+			r.line.WriteHistory(f)
+			f.Close()
+		}()
+	}
+
+	fmt.Printf("Connected to %s. Type an expression, a command such as primes 360, or :help.\n", r.opts.address)
+	for {
+		input, err := r.line.Prompt("calc> ")
+		if err == liner.ErrPromptAborted {
+			// Ctrl-C drops the line, Ctrl-D quits.
+			continue
+		}
+		if err == io.EOF {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+		r.line.AppendHistory(input)
+		if err := r.execute(input); err == errQuit {
+			return nil
+		}
+	}
+}
+
+// execute runs one line typed at the REPL and prints its result or error.
+func (r *repl) execute(input string) error {
+	if strings.HasPrefix(input, ":") {
+		err := r.setting(strings.Fields(input[1:]))
+		if err != nil && err != errQuit {
+			fmt.Fprintf(os.Stderr, "calc: %v\n", err)
+		}
+		return err
+	}
+
+	args, err := splitArgs(input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "calc: %v\n", err)
+		return err
+	}
+	cmd, ok := findCommand(args[0])
+	switch {
+	case !ok || isExpression(cmd, args):
+		// Anything else is an expression, which may start with a minus sign.
+		cmd, _ = findCommand("eval")
+		args = []string{"eval", "--", input}
+	case cmd.name == "repl":
+		fmt.Fprintln(os.Stderr, "calc: already in the REPL")
+		return nil
+	}
+
+	// Ctrl-C while a call runs cancels it rather than ending the REPL.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	baseContext = ctx
+	err = cmd.run(r.cc, r.opts, args[1:])
+	baseContext = context.Background()
+	stop()
+
+	if _, ok := err.(usageError); ok {
+		fmt.Fprintf(os.Stderr, "calc: %v\nusage: %s\n", err, cmd.usage)
+	} else if err != nil {
+		printError(r.opts, err)
+	}
+	return err
+}
+
+// setting runs a line starting with a colon: :help, :quit, or a setting
+// with or without a new value.
+func (r *repl) setting(fields []string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("missing setting after the colon; type :help")
+	}
+	switch fields[0] {
+	case "help":
+		r.help()
+		return nil
+	case "quit", "exit":
+		return errQuit
+	}
+
+	for _, s := range replSettings {
+		if s.name != fields[0] {
+			continue
+		}
+		switch len(fields) {
+		case 1:
+			fmt.Println(s.get(r.opts))
+			return nil
+		case 2:
+		default:
+			return fmt.Errorf("usage: %s", s.usage)
+		}
+
+		opts := r.opts
+		if err := s.set(&opts, fields[1]); err != nil {
+			return err
+		}
+		// Sessions belong to one server, so a new address needs a new one.
+		moved := opts.address != r.opts.address
+		if s.redial {
+			cc, err := dial(opts)
+			if err != nil {
+				return fmt.Errorf("could not connect to server: %v", err)
+			}
+			if moved {
+				r.endSession()
+				opts.session = ""
+			}
+			r.cc.Close()
+			r.cc = cc
+		}
+		r.opts = opts
+		if moved {
+			r.newSession()
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown setting %q; type :help", fields[0])
+}
+
+// newSession creates the session expressions are evaluated in. Without one
+// the REPL still works, but forgets variables from one line to the next.
+func (r *repl) newSession() {
+	ctx, cancel := callContext(r.opts.timeout)
+	defer cancel()
+
+	res, err := calculatorpb.NewCalculatorServiceClient(r.cc).CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "calc: could not create a session, so variables are not kept: %v\n", status.Convert(err).Message())
+		return
+	}
+	r.opts.session = res.SessionId
+	r.ownSession = true
+}
+
+// endSession deletes the session newSession created. One left behind expires
+// on its own, so errors are ignored.
+func (r *repl) endSession() {
+	if !r.ownSession {
+		return
+	}
+	ctx, cancel := callContext(r.opts.timeout)
+	defer cancel()
+
+	calculatorpb.NewCalculatorServiceClient(r.cc).DeleteSession(ctx, &calculatorpb.DeleteSessionRequest{SessionId: r.opts.session})
+	r.opts.session = ""
+	r.ownSession = false
+}
+
+// isExpression reports whether a line starting with the name of cmd is an
+// expression instead, as sum = 3 and max (1, 2) are: the next word starts
+// with an assignment, an operator or a parenthesis. The arguments of eval
+// are an expression already, so only an assignment makes eval a variable.
+func isExpression(cmd command, args []string) bool {
+	if len(args) < 2 {
+		return false
+	}
+	next := args[1]
+	if strings.HasPrefix(next, "=") {
+		return true
+	}
+	if cmd.name == "eval" {
+		return false
+	}
+	// A minus sign followed by anything is a negative number or a flag.
+	return next == "-" || strings.ContainsAny(next[:1], "+*/%^(")
+}
+
+func (r *repl) help() {
+	fmt.Println("Type an expression such as 2 * sqrt(16), or a command with its arguments:")
+	fmt.Println()
+	printCommands(os.Stdout)
+	fmt.Println()
+	fmt.Println("Settings, shown without a value and changed with one:")
+	for _, s := range replSettings {
+		fmt.Printf("  %-22s  now %s\n", s.usage, s.get(r.opts))
+	}
+	fmt.Println()
+	fmt.Println("  :help, :quit  this help, and leave (or Ctrl-D)")
+}
+
+// complete completes the command or setting at the start of a line.
+func (r *repl) complete(line string) []string {
+	if strings.ContainsAny(line, " \t") {
+		return nil
+	}
+	var names []string
+	for _, cmd := range commandTable() {
+		names = append(names, cmd.name)
+	}
+	for _, s := range replSettings {
+		names = append(names, ":"+s.name)
+	}
+	names = append(names, ":help", ":quit")
+
+	var matches []string
+	for _, name := range names {
+		if strings.HasPrefix(name, line) && name != "repl" {
+			matches = append(matches, name)
+		}
+	}
+	return matches
+}
+
+// splitArgs splits a line into arguments at spaces outside single or double
+// quotes, so that function define 'f(x) = x^2' is one definition.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	for _, c := range line {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// maxWindowSize bounds the numbers a sliding window may hold.
+	maxWindowSize = 1 << 20
+	// minWindowDuration is the shortest tumbling window.
+	minWindowDuration = 10 * time.Millisecond
+)
+
+func (s *server) RunningAggregate(stream calculatorpb.CalculatorService_RunningAggregateServer) error {
+	ctx := stream.Context()
+
+	// Tumbling windows end whether or not numbers arrive, so they are closed
+	// on a goroutine of their own, which is stopped and waited for before the
+	// handler returns. mu guards the aggregator and stream.Send.
+	var (
+		mu          sync.Mutex
+		agg         *runningAggregator
+		tumbling    bool
+		windowStart time.Time
+		last        []float64
+		// windowErr is the error that ended closeWindows.
+		windowErr error
+	)
+	send := func(closed bool) error {
+		values := agg.values()
+		if !closed && agg.emit == calculatorpb.EmitMode_EMIT_MODE_ON_CHANGE && slices.Equal(values, last) {
+			return nil
+		}
+		last = values
+		res := &calculatorpb.RunningAggregateResponse{WindowClosed: closed}
+		for i, a := range agg.aggregates {
+			res.Values = append(res.Values, &calculatorpb.AggregateValue{Aggregate: a, Value: values[i]})
+		}
+		if tumbling {
+			res.WindowStartMs = windowStart.UnixMilli()
+		}
+		if err := stream.Send(res); err != nil {
+			return streamError(ctx, err, "sending aggregates")
+		}
+		return nil
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer close(stop)
+	closeWindows := func(interval time.Duration) {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				mu.Lock()
+				if agg.count() > 0 {
+					windowErr = send(true)
+				}
+				agg.reset()
+				last = nil
+				windowStart = now
+				failed := windowErr != nil
+				mu.Unlock()
+				if failed {
+					// The handler returns the error with its next message.
+					return
+				}
+			}
+		}
+	}
+
+	// add handles a message of the client. mu must be held.
+	add := func(req *calculatorpb.RunningAggregateRequest) error {
+		if agg == nil {
+			var err error
+			if agg, err = newRunningAggregator(req); err != nil {
+				return err
+			}
+			if req.GetWindow() == calculatorpb.WindowType_WINDOW_TYPE_TUMBLING_TIME {
+				tumbling = true
+				windowStart = time.Now()
+				wg.Add(1)
+				go closeWindows(time.Duration(req.GetWindowMs()) * time.Millisecond)
+			}
+		} else if hasAggregateSettings(req) {
+			return requestError{
+				code:        codes.InvalidArgument,
+				reason:      "LATE_SETTINGS",
+				field:       "aggregates",
+				description: "settings may only be set in the first message",
+				message:     "Aggregates and window settings must be sent in the first message of the stream",
+			}.err()
+		}
+
+		for _, x := range req.GetNumbers() {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return requestError{
+					code:        codes.InvalidArgument,
+					reason:      "INVALID_NUMBER",
+					field:       "numbers",
+					description: "must be finite",
+					message:     fmt.Sprintf("Cannot aggregate %v", x),
+				}.err()
+			}
+			agg.add(x)
+		}
+		if len(req.GetNumbers()) > 0 {
+			return send(false)
+		}
+		return nil
+	}
+
+	for {
+		req, err := stream.Recv()
+		mu.Lock()
+		switch {
+		case windowErr != nil:
+			err = windowErr
+		case err == io.EOF:
+			// The last tumbling window ends early.
+			if tumbling && agg.count() > 0 {
+				err = send(true)
+			} else {
+				err = nil
+			}
+		case err != nil:
+			err = streamError(ctx, err, "reading client stream")
+		default:
+			if err = add(req); err == nil {
+				mu.Unlock()
+				continue
+			}
+		}
+		mu.Unlock()
+		return err
+	}
+}
+
+func hasAggregateSettings(req *calculatorpb.RunningAggregateRequest) bool {
+	return len(req.GetAggregates()) > 0 || req.GetWindow() != 0 || req.GetWindowSize() != 0 ||
+		req.GetWindowMs() != 0 || req.GetEwmaAlpha() != 0 || req.GetEmit() != 0
+}
+
+// runningAggregator maintains aggregates of the numbers in a window. A
+// sliding window keeps its numbers in a ring and its minimum and maximum in
+// monotonic queues, so every update takes constant amortized time.
+type runningAggregator struct {
+	aggregates []calculatorpb.Aggregate
+	emit       calculatorpb.EmitMode
+	alpha      float64
+	size       int // of a sliding window, else 0
+
+	n        uint64
+	sum      float64
+	min, max float64
+	ewma     float64
+
+	ring      []float64
+	head      int
+	seq       uint64 // of the next number
+	evictions int
+	minQueue  []sequenced
+	maxQueue  []sequenced
+}
+
+type sequenced struct {
+	seq uint64
+	x   float64
+}
+
+func newRunningAggregator(req *calculatorpb.RunningAggregateRequest) (*runningAggregator, error) {
+	invalid := func(field, description string) error {
+		return requestError{
+			code:        codes.InvalidArgument,
+			reason:      "INVALID_SETTING",
+			field:       field,
+			description: description,
+			message:     fmt.Sprintf("Invalid %s: %s", field, description),
+		}.err()
+	}
+
+	if len(req.GetAggregates()) == 0 {
+		return nil, invalid("aggregates", "at least one aggregate is required")
+	}
+	for _, a := range req.GetAggregates() {
+		if _, ok := calculatorpb.Aggregate_name[int32(a)]; !ok || a == calculatorpb.Aggregate_AGGREGATE_UNSPECIFIED {
+			return nil, invalid("aggregates", fmt.Sprintf("%v is not an aggregate", a))
+		}
+		if a == calculatorpb.Aggregate_AGGREGATE_EWMA && !(req.GetEwmaAlpha() > 0 && req.GetEwmaAlpha() <= 1) {
+			return nil, invalid("ewma_alpha", "must be above 0 and at most 1")
+		}
+	}
+	if _, ok := calculatorpb.EmitMode_name[int32(req.GetEmit())]; !ok {
+		return nil, invalid("emit", fmt.Sprintf("%v is not an emit mode", req.GetEmit()))
+	}
+
+	a := &runningAggregator{
+		aggregates: req.GetAggregates(),
+		emit:       req.GetEmit(),
+		alpha:      req.GetEwmaAlpha(),
+	}
+	switch req.GetWindow() {
+	case calculatorpb.WindowType_WINDOW_TYPE_CUMULATIVE:
+	case calculatorpb.WindowType_WINDOW_TYPE_SLIDING_COUNT:
+		if req.GetWindowSize() == 0 || req.GetWindowSize() > maxWindowSize {
+			return nil, invalid("window_size", "must be between 1 and "+strconv.Itoa(maxWindowSize))
+		}
+		a.size = int(req.GetWindowSize())
+	case calculatorpb.WindowType_WINDOW_TYPE_TUMBLING_TIME:
+		if time.Duration(req.GetWindowMs())*time.Millisecond < minWindowDuration {
+			return nil, invalid("window_ms", fmt.Sprintf("must be at least %d", minWindowDuration.Milliseconds()))
+		}
+	default:
+		return nil, invalid("window", fmt.Sprintf("%v is not a window type", req.GetWindow()))
+	}
+	return a, nil
+}
+
+func (a *runningAggregator) count() uint64 {
+	if a.size > 0 {
+		return uint64(len(a.ring))
+	}
+	return a.n
+}
+
+func (a *runningAggregator) add(x float64) {
+	if a.n == 0 {
+		a.ewma = x
+	} else {
+		a.ewma = a.alpha*x + (1-a.alpha)*a.ewma
+	}
+	a.n++
+
+	if a.size == 0 {
+		if a.n == 1 || x < a.min {
+			a.min = x
+		}
+		if a.n == 1 || x > a.max {
+			a.max = x
+		}
+		a.sum += x
+		return
+	}
+
+	if len(a.ring) < a.size {
+		a.ring = append(a.ring, x)
+		a.sum += x
+	} else {
+		a.sum += x - a.ring[a.head]
+		a.ring[a.head] = x
+		a.head = (a.head + 1) % a.size
+		// Adding and subtracting accumulates rounding errors; start over
+		// once per window.
+		if a.evictions++; a.evictions == a.size {
+			a.evictions = 0
+			a.sum = 0
+			for _, v := range a.ring {
+				a.sum += v
+			}
+		}
+	}
+
+	oldest := a.seq + 1 - uint64(len(a.ring))
+	for len(a.minQueue) > 0 && a.minQueue[len(a.minQueue)-1].x >= x {
+		a.minQueue = a.minQueue[:len(a.minQueue)-1]
+	}
+	a.minQueue = append(a.minQueue, sequenced{a.seq, x})
+	if a.minQueue[0].seq < oldest {
+		a.minQueue = a.minQueue[1:]
+	}
+	for len(a.maxQueue) > 0 && a.maxQueue[len(a.maxQueue)-1].x <= x {
+		a.maxQueue = a.maxQueue[:len(a.maxQueue)-1]
+	}
+	a.maxQueue = append(a.maxQueue, sequenced{a.seq, x})
+	if a.maxQueue[0].seq < oldest {
+		a.maxQueue = a.maxQueue[1:]
+	}
+	a.seq++
+}
+
+// reset empties the window at the end of a tumbling window.
+func (a *runningAggregator) reset() {
+	a.n, a.sum, a.min, a.max, a.ewma = 0, 0, 0, 0, 0
+}
+
+func (a *runningAggregator) values() []float64 {
+	values := make([]float64, len(a.aggregates))
+	for i, agg := range a.aggregates {
+		switch agg {
+		case calculatorpb.Aggregate_AGGREGATE_COUNT:
+			values[i] = float64(a.count())
+		case calculatorpb.Aggregate_AGGREGATE_SUM:
+			values[i] = a.sum
+		case calculatorpb.Aggregate_AGGREGATE_MEAN:
+			values[i] = a.sum / float64(a.count())
+		case calculatorpb.Aggregate_AGGREGATE_MIN:
+			values[i] = a.min
+			if a.size > 0 {
+				values[i] = a.minQueue[0].x
+			}
+		case calculatorpb.Aggregate_AGGREGATE_MAX:
+			values[i] = a.max
+			if a.size > 0 {
+				values[i] = a.maxQueue[0].x
+			}
+		case calculatorpb.Aggregate_AGGREGATE_EWMA:
+			values[i] = a.ewma
+		}
+	}
+	return values
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+// int32Sum is the result of adding two int32 numbers.
+type int32Sum struct {
+	// sum is the int32 result; wide is the exact result in
+	// OVERFLOW_MODE_WIDENED.
+	sum        int32
+	wide       int64
+	overflowed bool
+}
+
+// addInt32 adds a and b, handling a sum outside the int32 range as mode says.
+func addInt32(a, b int32, mode calculatorpb.OverflowMode) (int32Sum, error) {
+	exact := int64(a) + int64(b)
+	fits := exact >= math.MinInt32 && exact <= math.MaxInt32
+
+	switch mode {
+	case calculatorpb.OverflowMode_OVERFLOW_MODE_CHECKED:
+		if !fits {
+			return int32Sum{}, requestError{
+				code:   codes.OutOfRange,
+				reason: "INT32_OVERFLOW",
+				message: fmt.Sprintf("%d + %d = %d does not fit in an int32; set overflow_mode for saturating, wrapping or widened arithmetic",
+					a, b, exact),
+				metadata: map[string]string{
+					"first_number":  strconv.Itoa(int(a)),
+					"second_number": strconv.Itoa(int(b)),
+					"sum":           strconv.FormatInt(exact, 10),
+				},
+			}.err()
+		}
+		return int32Sum{sum: int32(exact)}, nil
+	case calculatorpb.OverflowMode_OVERFLOW_MODE_SATURATING:
+		switch {
+		case exact > math.MaxInt32:
+			return int32Sum{sum: math.MaxInt32, overflowed: true}, nil
+		case exact < math.MinInt32:
+			return int32Sum{sum: math.MinInt32, overflowed: true}, nil
+		}
+		return int32Sum{sum: int32(exact)}, nil
+	case calculatorpb.OverflowMode_OVERFLOW_MODE_WRAPPING:
+		return int32Sum{sum: a + b, overflowed: !fits}, nil
+	case calculatorpb.OverflowMode_OVERFLOW_MODE_WIDENED:
+		return int32Sum{wide: exact}, nil
+	}
+
+	return int32Sum{}, requestError{
+		code:        codes.InvalidArgument,
+		reason:      "UNKNOWN_OVERFLOW_MODE",
+		field:       "overflow_mode",
+		description: fmt.Sprintf("unknown mode %d", mode),
+		message:     fmt.Sprintf("Unknown overflow mode %d", mode),
+		metadata:    map[string]string{"overflow_mode": strconv.Itoa(int(mode))},
+	}.err()
+}
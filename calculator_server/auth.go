@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// Callers authenticate with one of these request headers.
+const (
+	apiKeyHeader        = "x-api-key"
+	authorizationHeader = "authorization"
+)
+
+// jwtLeeway allows for clock skew between the token issuer and the server.
+const jwtLeeway = 30 * time.Second
+
+// publicMethodPrefixes are callable without credentials and regardless of
+// the policy, so that load balancers and orchestrators can probe the server.
+var publicMethodPrefixes = []string{
+	"/grpc.health.v1.Health/",
+}
+
+// principal is an authenticated caller.
+type principal struct {
+	// Name is the name of the API key or the subject of the token.
+	Name string
+	// Method is how the caller authenticated: "api-key" or "jwt".
+	Method string
+	// Roles come from the API key entry or the token's roles claim.
+	Roles []string
+	// Claims are all claims of the token; nil for API keys.
+	Claims map[string]interface{}
+}
+
+type principalKey struct{}
+
+// principalFromContext returns the authenticated caller of an RPC, if any.
+func principalFromContext(ctx context.Context) (principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(principal)
+	return p, ok
+}
+
+// apiKey is an entry of the API keys file.
+type apiKey struct {
+	Name  string   `yaml:"name"`
+	Key   string   `yaml:"key"`
+	Roles []string `yaml:"roles"`
+}
+
+// jwtKey is a key that signed tokens are verified against.
+type jwtKey struct {
+	id        string
+	algorithm string
+	key       jwt.VerificationKey
+}
+
+// authenticator checks the credentials of every call.
+type authenticator struct {
+	apiKeys  []apiKey
+	jwtKeys  []jwtKey
+	issuer   string
+	audience string
+}
+
+func newAuthenticator(cfg authConfig) (*authenticator, error) {
+	a := &authenticator{
+		issuer:   cfg.JWT.Issuer,
+		audience: cfg.JWT.Audience,
+	}
+
+	if cfg.APIKeysFile != "" {
+		keys, err := loadAPIKeys(cfg.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		a.apiKeys = keys
+	}
+
+	for _, k := range cfg.JWT.Keys {
+		key, err := loadJWTKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("loading JWT key %q: %v", k.ID, err)
+		}
+		a.jwtKeys = append(a.jwtKeys, key)
+	}
+
+	return a, nil
+}
+
+func loadAPIKeys(path string) ([]apiKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading API keys: %v", err)
+	}
+	var keys []apiKey
+	if err := yaml.UnmarshalStrict(data, &keys); err != nil {
+		return nil, fmt.Errorf("parsing API keys file %s: %v", path, err)
+	}
+	for i, k := range keys {
+		if k.Name == "" || k.Key == "" {
+			return nil, fmt.Errorf("API keys file %s: entry %d needs a name and a key", path, i+1)
+		}
+	}
+	return keys, nil
+}
+
+func loadJWTKey(cfg jwtKeyConfig) (jwtKey, error) {
+	data, err := os.ReadFile(cfg.File)
+	if err != nil {
+		return jwtKey{}, err
+	}
+
+	key := jwtKey{id: cfg.ID, algorithm: cfg.Algorithm}
+	switch cfg.Algorithm {
+	case "HS256", "HS384", "HS512":
+		secret := bytes.TrimSpace(data)
+		if len(secret) == 0 {
+			return jwtKey{}, fmt.Errorf("%s is empty", cfg.File)
+		}
+		key.key = secret
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		if key.key, err = jwt.ParseRSAPublicKeyFromPEM(data); err != nil {
+			return jwtKey{}, err
+		}
+	default:
+		return jwtKey{}, fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+	}
+	return key, nil
+}
+
+// authenticate returns the caller identified by the credentials in ctx.
+func (a *authenticator) authenticate(ctx context.Context) (principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if keys := md.Get(apiKeyHeader); len(keys) > 0 {
+		return a.checkAPIKey(keys[0])
+	}
+	if values := md.Get(authorizationHeader); len(values) > 0 {
+		scheme, token, _ := strings.Cut(values[0], " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return principal{}, status.Error(codes.Unauthenticated, "authorization header must be a bearer token")
+		}
+		return a.verifyToken(token)
+	}
+	return principal{}, status.Errorf(codes.Unauthenticated, "missing credentials: send an API key in %s or a bearer token in %s", apiKeyHeader, authorizationHeader)
+}
+
+func (a *authenticator) checkAPIKey(key string) (principal, error) {
+	for _, k := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k.Key)) == 1 {
+			return principal{Name: k.Name, Method: "api-key", Roles: k.Roles}, nil
+		}
+	}
+	return principal{}, status.Error(codes.Unauthenticated, "invalid API key")
+}
+
+func (a *authenticator) verifyToken(token string) (principal, error) {
+	if len(a.jwtKeys) == 0 {
+		return principal{}, status.Error(codes.Unauthenticated, "bearer tokens are not accepted")
+	}
+
+	methods := make([]string, 0, len(a.jwtKeys))
+	for _, k := range a.jwtKeys {
+		methods = append(methods, k.algorithm)
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(jwtLeeway),
+	}
+	if a.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.issuer))
+	}
+	if a.audience != "" {
+		opts = append(opts, jwt.WithAudience(a.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, a.keyFunc, opts...); err != nil {
+		return principal{}, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return principal{}, status.Error(codes.Unauthenticated, "invalid token: no subject")
+	}
+	roles, err := stringsClaim(claims, "roles")
+	if err != nil {
+		return principal{}, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	return principal{Name: subject, Method: "jwt", Roles: roles, Claims: claims}, nil
+}
+
+// stringsClaim returns the claim name, which must be a list of strings if
+// present.
+func stringsClaim(claims jwt.MapClaims, name string) ([]string, error) {
+	v, ok := claims[name]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s claim must be a list of strings", name)
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s claim must be a list of strings", name)
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// keyFunc returns the keys a token may be signed with: the one named by its
+// kid header, or every key for its algorithm if it has none.
+func (a *authenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	var set jwt.VerificationKeySet
+	for _, k := range a.jwtKeys {
+		if k.algorithm == token.Method.Alg() && (kid == "" || kid == k.id) {
+			set.Keys = append(set.Keys, k.key)
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return set, nil
+}
+
+func isPublicMethod(method string) bool {
+	for _, prefix := range publicMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isPublicMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	p, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logPrincipal(ctx, p)
+	return handler(context.WithValue(ctx, principalKey{}, p), req)
+}
+
+func (a *authenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isPublicMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	p, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	logPrincipal(ss.Context(), p)
+	return handler(srv, &authenticatedStream{ss, context.WithValue(ss.Context(), principalKey{}, p)})
+}
+
+// authenticatedStream carries the caller's principal to the handler.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+// Operands are parsed with these patterns rather than accepted in every
+// syntax math/big knows: an exponent such as "1e999999999" would make
+// big.Rat.SetString itself compute a huge number.
+var (
+	bigIntPattern = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	bigRatPattern = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+|/[0-9]+)?$`)
+)
+
+// bitsPerDigit is log2(10), the number of bits a decimal digit takes.
+const bitsPerDigit = 3.321928094887362
+
+func (s *server) BigIntArithmetic(ctx context.Context, req *calculatorpb.BigIntRequest) (*calculatorpb.BigIntResponse, error) {
+	a, err := s.parseBigInt("a", req.GetA())
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.parseBigInt("b", req.GetB())
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(big.Int)
+	switch req.GetOperation() {
+	case calculatorpb.BigOperation_BIG_OPERATION_ADD:
+		result.Add(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_SUBTRACT:
+		result.Sub(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_MULTIPLY:
+		if a.BitLen()+b.BitLen() > s.maxBits()+1 {
+			return nil, s.resultTooLarge()
+		}
+		result.Mul(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_DIVIDE:
+		if b.Sign() == 0 {
+			return nil, divisionByZero("b")
+		}
+		result.Quo(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_MODULO:
+		if b.Sign() == 0 {
+			return nil, divisionByZero("b")
+		}
+		result.Rem(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_POWER:
+		if b.Sign() < 0 {
+			return nil, invalidNumber("b", req.GetB(), "must not be negative for integers")
+		}
+		// |a| >= 2 doubles the result at least b times.
+		if a.CmpAbs(big.NewInt(1)) > 0 && (b.Cmp(big.NewInt(int64(s.maxBits()))) > 0 || b.Int64()*int64(a.BitLen()-1) > int64(s.maxBits())) {
+			return nil, s.resultTooLarge()
+		}
+		result.Exp(a, b, nil)
+	case calculatorpb.BigOperation_BIG_OPERATION_MOD_POW:
+		m, err := s.parseBigInt("modulus", req.GetModulus())
+		if err != nil {
+			return nil, err
+		}
+		if m.Sign() <= 0 {
+			return nil, invalidNumber("modulus", req.GetModulus(), "must be positive")
+		}
+		// Exp squares modulo m once per bit of b, which takes seconds well
+		// below max_digits, so the bit lengths are bounded together.
+		if int64(b.BitLen())*int64(m.BitLen()) > s.big.ModPowMaxWork {
+			return nil, s.tooMuchWork()
+		}
+		if result.Exp(a, b, m) == nil {
+			return nil, requestError{
+				code:        codes.InvalidArgument,
+				reason:      "NO_MODULAR_INVERSE",
+				field:       "a",
+				description: "has no inverse modulo modulus",
+				message:     "a has no inverse modulo modulus, so it cannot be raised to a negative power",
+			}.err()
+		}
+	default:
+		return nil, unsupportedOperation(req.GetOperation(), "integers")
+	}
+
+	if result.BitLen() > s.maxBits() {
+		return nil, s.resultTooLarge()
+	}
+	return &calculatorpb.BigIntResponse{Result: result.String()}, nil
+}
+
+func (s *server) BigRatArithmetic(ctx context.Context, req *calculatorpb.BigRatRequest) (*calculatorpb.BigRatResponse, error) {
+	a, err := s.parseBigRat("a", req.GetA())
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.parseBigRat("b", req.GetB())
+	if err != nil {
+		return nil, err
+	}
+	if int(req.GetDecimalPlaces()) > s.big.MaxDigits {
+		return nil, requestError{
+			code:        codes.InvalidArgument,
+			reason:      "TOO_MANY_DIGITS",
+			field:       "decimal_places",
+			description: fmt.Sprintf("must be at most %d", s.big.MaxDigits),
+			message:     fmt.Sprintf("At most %d decimal places are allowed", s.big.MaxDigits),
+		}.err()
+	}
+
+	result := new(big.Rat)
+	switch req.GetOperation() {
+	case calculatorpb.BigOperation_BIG_OPERATION_ADD:
+		result.Add(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_SUBTRACT:
+		result.Sub(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_MULTIPLY:
+		if ratBitLen(a)+ratBitLen(b) > s.maxBits()+2 {
+			return nil, s.resultTooLarge()
+		}
+		result.Mul(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_DIVIDE:
+		if b.Sign() == 0 {
+			return nil, divisionByZero("b")
+		}
+		if ratBitLen(a)+ratBitLen(b) > s.maxBits()+2 {
+			return nil, s.resultTooLarge()
+		}
+		result.Quo(a, b)
+	case calculatorpb.BigOperation_BIG_OPERATION_POWER:
+		if !b.IsInt() {
+			return nil, invalidNumber("b", req.GetB(), "must be an integer")
+		}
+		exponent := b.Num()
+		if exponent.Sign() < 0 && a.Sign() == 0 {
+			return nil, divisionByZero("a")
+		}
+		e := new(big.Int).Abs(exponent)
+		// Anything but 0, 1 and -1 at least doubles its numerator or
+		// denominator e times.
+		if ratBitLen(a) > 2 && (e.Cmp(big.NewInt(int64(s.maxBits()))) > 0 || e.Int64()*int64(ratBitLen(a)-2) > int64(s.maxBits())) {
+			return nil, s.resultTooLarge()
+		}
+		num := new(big.Int).Exp(a.Num(), e, nil)
+		den := new(big.Int).Exp(a.Denom(), e, nil)
+		if exponent.Sign() < 0 {
+			num, den = den, num
+		}
+		result.SetFrac(num, den)
+	default:
+		return nil, unsupportedOperation(req.GetOperation(), "rationals")
+	}
+
+	if ratBitLen(result) > s.maxBits() {
+		return nil, s.resultTooLarge()
+	}
+	res := &calculatorpb.BigRatResponse{Result: result.RatString()}
+	if req.GetDecimalPlaces() > 0 {
+		res.Decimal = result.FloatString(int(req.GetDecimalPlaces()))
+	}
+	return res, nil
+}
+
+// maxBits is the size in bits of the largest number the server computes with.
+func (s *server) maxBits() int {
+	return int(math.Ceil(float64(s.big.MaxDigits) * bitsPerDigit))
+}
+
+func (s *server) parseBigInt(field, value string) (*big.Int, error) {
+	if !bigIntPattern.MatchString(value) {
+		return nil, invalidNumber(field, value, "must be a decimal integer")
+	}
+	if err := s.checkDigits(field, value); err != nil {
+		return nil, err
+	}
+	n, _ := new(big.Int).SetString(value, 10)
+	return n, nil
+}
+
+func (s *server) parseBigRat(field, value string) (*big.Rat, error) {
+	if !bigRatPattern.MatchString(value) {
+		return nil, invalidNumber(field, value, "must be a fraction such as -3/4 or a decimal such as 1.25")
+	}
+	if err := s.checkDigits(field, value); err != nil {
+		return nil, err
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, invalidNumber(field, value, "denominator must not be zero")
+	}
+	return r, nil
+}
+
+// checkDigits rejects an operand with more than the allowed number of digits.
+func (s *server) checkDigits(field, value string) error {
+	digits := 0
+	for _, c := range value {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+	if digits <= s.big.MaxDigits {
+		return nil
+	}
+	return requestError{
+		code:        codes.InvalidArgument,
+		reason:      "TOO_MANY_DIGITS",
+		field:       field,
+		description: fmt.Sprintf("has %d digits, at most %d are allowed", digits, s.big.MaxDigits),
+		message:     fmt.Sprintf("%s has %d digits, at most %d are allowed", field, digits, s.big.MaxDigits),
+		metadata:    map[string]string{"max_digits": strconv.Itoa(s.big.MaxDigits)},
+	}.err()
+}
+
+func (s *server) resultTooLarge() error {
+	return requestError{
+		code:     codes.OutOfRange,
+		reason:   "RESULT_TOO_LARGE",
+		message:  fmt.Sprintf("The result would have more than %d digits", s.big.MaxDigits),
+		metadata: map[string]string{"max_digits": strconv.Itoa(s.big.MaxDigits)},
+	}.err()
+}
+
+func (s *server) tooMuchWork() error {
+	return requestError{
+		code:     codes.OutOfRange,
+		reason:   "TOO_MUCH_WORK",
+		message:  fmt.Sprintf("The bit lengths of b and the modulus multiply to more than %d", s.big.ModPowMaxWork),
+		metadata: map[string]string{"max_work": strconv.FormatInt(s.big.ModPowMaxWork, 10)},
+	}.err()
+}
+
+func invalidNumber(field, value, description string) error {
+	const maxQuoted = 40
+	if len(value) > maxQuoted {
+		value = value[:maxQuoted] + "..."
+	}
+	return requestError{
+		code:        codes.InvalidArgument,
+		reason:      "INVALID_NUMBER",
+		field:       field,
+		description: description,
+		message:     fmt.Sprintf("Invalid %s %q: %s", field, value, description),
+	}.err()
+}
+
+func divisionByZero(field string) error {
+	return requestError{
+		code:        codes.InvalidArgument,
+		reason:      "DIVISION_BY_ZERO",
+		field:       field,
+		description: "must not be zero",
+		message:     "Division by zero",
+	}.err()
+}
+
+func unsupportedOperation(op calculatorpb.BigOperation, kind string) error {
+	return requestError{
+		code:        codes.InvalidArgument,
+		reason:      "UNSUPPORTED_OPERATION",
+		field:       "operation",
+		description: fmt.Sprintf("%v is not supported for %s", op, kind),
+		message:     fmt.Sprintf("%v is not supported for %s", op, kind),
+	}.err()
+}
+
+// ratBitLen is the size of r: the bits of its numerator and denominator.
+func ratBitLen(r *big.Rat) int {
+	return r.Num().BitLen() + r.Denom().BitLen()
+}
@@ -0,0 +1,451 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// envPrefix is prepended to every flag name to get its environment variable,
+// e.g. -tls-cert can also be set with CALCULATOR_TLS_CERT.
+const envPrefix = "CALCULATOR_"
+
+type config struct {
+	Address    string       `yaml:"address"`
+	Reflection bool         `yaml:"reflection"`
+	LogLevel   string       `yaml:"log_level"`
+	LogFormat  string       `yaml:"log_format"`
+	TLS        tlsConfig    `yaml:"tls"`
+	Limits     limitsConfig `yaml:"limits"`
+	// ShutdownDrainDelay is how long health checks report NOT_SERVING after
+	// SIGINT or SIGTERM before the server stops accepting RPCs, so that load
+	// balancers move traffic away first.
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay"`
+	// ShutdownGracePeriod is how long active calls may run after SIGINT or
+	// SIGTERM before the server stops forcibly.
+	ShutdownGracePeriod time.Duration `yaml:"shutdown_grace_period"`
+	// HealthCheckInterval is how often dependencies are checked for the
+	// gRPC health service.
+	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+	// MetricsAddress is where Prometheus metrics are served on /metrics.
+	// Empty disables metrics.
+	MetricsAddress string        `yaml:"metrics_address"`
+	Tracing        tracingConfig `yaml:"tracing"`
+	Auth           authConfig    `yaml:"auth"`
+	// RateLimits limit how often and how many calls at once each client may
+	// make. The first rule covering a method applies to it.
+	RateLimits []rateLimitConfig `yaml:"rate_limits"`
+	BigNumbers bigNumbersConfig  `yaml:"big_numbers"`
+	Statistics statisticsConfig  `yaml:"statistics"`
+	Sessions   sessionsConfig    `yaml:"sessions"`
+	Functions  functionsConfig   `yaml:"functions"`
+}
+
+type tlsConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ClientCAFile turns on mutual TLS: clients must present a certificate
+	// signed by this CA.
+	ClientCAFile string `yaml:"client_ca_file"`
+	// WatchFiles and ReloadOnSighup reload the certificate without a restart.
+	WatchFiles     bool `yaml:"watch_files"`
+	ReloadOnSighup bool `yaml:"reload_on_sighup"`
+}
+
+type authConfig struct {
+	// Enabled rejects calls without valid credentials. Health checks are
+	// always allowed.
+	Enabled bool `yaml:"enabled"`
+	// APIKeysFile is a YAML list of accepted API keys, each with a name and
+	// a key.
+	APIKeysFile string    `yaml:"api_keys_file"`
+	JWT         jwtConfig `yaml:"jwt"`
+	// PolicyFile restricts which callers may call which methods. It is
+	// reloaded when it changes and on SIGHUP. Empty allows every call.
+	PolicyFile string `yaml:"policy_file"`
+}
+
+type jwtConfig struct {
+	// Issuer and Audience, if set, must match the iss and aud claims.
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// Keys verify bearer tokens. A token with a kid header is checked
+	// against the key with that ID only.
+	Keys []jwtKeyConfig `yaml:"keys"`
+}
+
+type jwtKeyConfig struct {
+	ID string `yaml:"id"`
+	// Algorithm is HS256, HS384 or HS512 for a file holding a shared secret,
+	// or RS256, RS384, RS512, PS256, PS384 or PS512 for a PEM public key.
+	Algorithm string `yaml:"algorithm"`
+	File      string `yaml:"file"`
+}
+
+type rateLimitConfig struct {
+	// Methods are full method names; * matches any part of a name.
+	Methods []string `yaml:"methods"`
+	// Rate is how many calls per second each client may start on average,
+	// and Burst how many it may start at once. Zero Rate means no limit.
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+	// MaxConcurrent is how many calls or streams each client may have open
+	// at once. Zero means no limit.
+	MaxConcurrent int `yaml:"max_concurrent"`
+}
+
+type bigNumbersConfig struct {
+	// MaxDigits is the most decimal digits an operand or result of the
+	// arbitrary-precision RPCs may have. A rational counts the digits of its
+	// numerator and denominator.
+	MaxDigits int `yaml:"max_digits"`
+	// FactorizeBudget is the most time Factorize spends on one number before
+	// it returns the factors found so far and marks the result incomplete.
+	FactorizeBudget time.Duration `yaml:"factorize_budget"`
+	// ModPowMaxWork is the most the bit lengths of the exponent and the
+	// modulus of MOD_POW may multiply to, which bounds the time one call
+	// takes.
+	ModPowMaxWork int64 `yaml:"mod_pow_max_work"`
+}
+
+type statisticsConfig struct {
+	// ExactLimit is how many numbers ComputeStatistics keeps to compute the
+	// median and percentiles exactly. Longer streams switch to a sketch
+	// with a bounded relative error.
+	ExactLimit int `yaml:"exact_limit"`
+}
+
+type sessionsConfig struct {
+	// TTL is how long an expression session lives after its last use.
+	TTL time.Duration `yaml:"ttl"`
+	// MaxSessions bounds how many sessions the server keeps at once.
+	MaxSessions int `yaml:"max_sessions"`
+	// MaxVariables bounds the variables of one session, and so its memory.
+	MaxVariables int `yaml:"max_variables"`
+}
+
+type functionsConfig struct {
+	// File keeps the user-defined functions across restarts. Empty keeps
+	// them in memory only.
+	File string `yaml:"file"`
+	// MaxPerTenant bounds the functions one tenant may define.
+	MaxPerTenant int `yaml:"max_per_tenant"`
+}
+
+type tracingConfig struct {
+	// Exporter is where spans are sent: none, stdout or otlp.
+	Exporter string `yaml:"exporter"`
+	// OTLPEndpoint is the host:port of the OTLP/gRPC collector.
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	// SampleRatio is the fraction of new traces that are recorded. Calls
+	// that are part of a trace follow the caller's sampling decision.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+type limitsConfig struct {
+	MaxRecvMsgSize       int           `yaml:"max_recv_msg_size"`
+	MaxSendMsgSize       int           `yaml:"max_send_msg_size"`
+	MaxConcurrentStreams uint          `yaml:"max_concurrent_streams"`
+	ConnectionTimeout    time.Duration `yaml:"connection_timeout"`
+}
+
+func defaultConfig() config {
+	return config{
+		Address:    "0.0.0.0:50051",
+		Reflection: true,
+		LogLevel:   "info",
+		LogFormat:  "json",
+		TLS: tlsConfig{
+			Enabled:        false,
+			CertFile:       "../ssl/server.crt",
+			KeyFile:        "../ssl/server.pem",
+			WatchFiles:     true,
+			ReloadOnSighup: true,
+		},
+		Limits: limitsConfig{
+			MaxRecvMsgSize:    4 << 20,
+			MaxSendMsgSize:    4 << 20,
+			ConnectionTimeout: 120 * time.Second,
+		},
+		ShutdownDrainDelay:  5 * time.Second,
+		ShutdownGracePeriod: 30 * time.Second,
+		HealthCheckInterval: 10 * time.Second,
+		MetricsAddress:      "0.0.0.0:9090",
+		BigNumbers: bigNumbersConfig{
+			MaxDigits:       10000,
+			FactorizeBudget: 10 * time.Second,
+			ModPowMaxWork:   1 << 26,
+		},
+		Statistics: statisticsConfig{
+			ExactLimit: 100000,
+		},
+		Sessions: sessionsConfig{
+			TTL:          30 * time.Minute,
+			MaxSessions:  10000,
+			MaxVariables: 1000,
+		},
+		Functions: functionsConfig{
+			MaxPerTenant: 100,
+		},
+		Tracing: tracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "localhost:4317",
+			SampleRatio:  1,
+		},
+	}
+}
+
+// flagSet binds every setting of cfg to a command-line flag.
+func (cfg *config) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("calculator_server", flag.ContinueOnError)
+	fs.StringVar(&cfg.Address, "address", cfg.Address, "address to listen on (host:port)")
+	fs.BoolVar(&cfg.Reflection, "reflection", cfg.Reflection, "register the gRPC reflection service")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "log level: debug, info, warn or error")
+	fs.StringVar(&cfg.LogFormat, "log-format", cfg.LogFormat, "log format: json (one object per line) or text")
+	fs.BoolVar(&cfg.TLS.Enabled, "tls", cfg.TLS.Enabled, "serve over TLS")
+	fs.StringVar(&cfg.TLS.CertFile, "tls-cert", cfg.TLS.CertFile, "server certificate file")
+	fs.StringVar(&cfg.TLS.KeyFile, "tls-key", cfg.TLS.KeyFile, "server private key file")
+	fs.BoolVar(&cfg.TLS.WatchFiles, "tls-watch", cfg.TLS.WatchFiles, "reload the certificate when its files change")
+	fs.BoolVar(&cfg.TLS.ReloadOnSighup, "tls-reload-on-sighup", cfg.TLS.ReloadOnSighup, "reload the certificate on SIGHUP")
+	fs.StringVar(&cfg.TLS.ClientCAFile, "tls-client-ca", cfg.TLS.ClientCAFile, "CA file for verifying client certificates (enables mutual TLS)")
+	fs.IntVar(&cfg.Limits.MaxRecvMsgSize, "max-recv-msg-size", cfg.Limits.MaxRecvMsgSize, "maximum message size in bytes the server can receive")
+	fs.IntVar(&cfg.Limits.MaxSendMsgSize, "max-send-msg-size", cfg.Limits.MaxSendMsgSize, "maximum message size in bytes the server can send")
+	fs.UintVar(&cfg.Limits.MaxConcurrentStreams, "max-concurrent-streams", cfg.Limits.MaxConcurrentStreams, "maximum concurrent streams per connection (0 means unlimited)")
+	fs.DurationVar(&cfg.Limits.ConnectionTimeout, "connection-timeout", cfg.Limits.ConnectionTimeout, "timeout for establishing new connections")
+	fs.DurationVar(&cfg.ShutdownDrainDelay, "shutdown-drain-delay", cfg.ShutdownDrainDelay, "how long health checks report NOT_SERVING after SIGINT/SIGTERM before new RPCs are refused")
+	fs.DurationVar(&cfg.ShutdownGracePeriod, "shutdown-grace-period", cfg.ShutdownGracePeriod, "how long active calls may run after SIGINT/SIGTERM")
+	fs.DurationVar(&cfg.HealthCheckInterval, "health-check-interval", cfg.HealthCheckInterval, "how often to check dependencies for the health service")
+	fs.StringVar(&cfg.MetricsAddress, "metrics-address", cfg.MetricsAddress, "address to serve Prometheus metrics on (empty disables metrics)")
+	fs.StringVar(&cfg.Tracing.Exporter, "tracing-exporter", cfg.Tracing.Exporter, "where to send trace spans: none, stdout or otlp")
+	fs.StringVar(&cfg.Tracing.OTLPEndpoint, "tracing-otlp-endpoint", cfg.Tracing.OTLPEndpoint, "OTLP/gRPC collector address (host:port)")
+	fs.Float64Var(&cfg.Tracing.SampleRatio, "tracing-sample-ratio", cfg.Tracing.SampleRatio, "fraction of new traces to record, from 0 to 1")
+	fs.BoolVar(&cfg.Auth.Enabled, "auth", cfg.Auth.Enabled, "require an API key or bearer token on every call")
+	fs.StringVar(&cfg.Auth.APIKeysFile, "auth-api-keys-file", cfg.Auth.APIKeysFile, "YAML file of accepted API keys")
+	fs.StringVar(&cfg.Auth.JWT.Issuer, "auth-jwt-issuer", cfg.Auth.JWT.Issuer, "required iss claim of bearer tokens")
+	fs.StringVar(&cfg.Auth.JWT.Audience, "auth-jwt-audience", cfg.Auth.JWT.Audience, "required aud claim of bearer tokens")
+	fs.StringVar(&cfg.Auth.PolicyFile, "auth-policy-file", cfg.Auth.PolicyFile, "YAML file of per-method authorization rules")
+	fs.IntVar(&cfg.BigNumbers.MaxDigits, "big-max-digits", cfg.BigNumbers.MaxDigits, "most decimal digits of a number in the arbitrary-precision RPCs")
+	fs.Int64Var(&cfg.BigNumbers.ModPowMaxWork, "big-mod-pow-max-work", cfg.BigNumbers.ModPowMaxWork, "most the bit lengths of the exponent and modulus of MOD_POW may multiply to")
+	fs.DurationVar(&cfg.BigNumbers.FactorizeBudget, "factorize-budget", cfg.BigNumbers.FactorizeBudget, "most time Factorize spends on one number")
+	fs.IntVar(&cfg.Statistics.ExactLimit, "statistics-exact-limit", cfg.Statistics.ExactLimit, "most numbers ComputeStatistics keeps for exact percentiles")
+	fs.DurationVar(&cfg.Sessions.TTL, "session-ttl", cfg.Sessions.TTL, "how long an expression session lives after its last use")
+	fs.IntVar(&cfg.Sessions.MaxSessions, "max-sessions", cfg.Sessions.MaxSessions, "most expression sessions kept at once")
+	fs.IntVar(&cfg.Sessions.MaxVariables, "session-max-variables", cfg.Sessions.MaxVariables, "most variables in one expression session")
+	fs.StringVar(&cfg.Functions.File, "functions-file", cfg.Functions.File, "file that keeps user-defined functions across restarts")
+	fs.IntVar(&cfg.Functions.MaxPerTenant, "max-functions", cfg.Functions.MaxPerTenant, "most user-defined functions per tenant")
+	return fs
+}
+
+// loadConfig builds the server configuration. Settings are applied in order
+// of increasing precedence: defaults, config file, environment variables and
+// finally command-line flags.
+func loadConfig(args []string) (config, error) {
+	flags := defaultConfig()
+	fs := flags.flagSet()
+	configFile := fs.String("config", os.Getenv(envPrefix+"CONFIG"), "path to a YAML config file")
+	if err := fs.Parse(args); err != nil {
+		return flags, err
+	}
+	if fs.NArg() > 0 {
+		return flags, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	// Start over from the defaults now that we know where the config file is,
+	// and layer the other sources on top of it.
+	cfg := defaultConfig()
+	if *configFile != "" {
+		data, err := os.ReadFile(*configFile)
+		if err != nil {
+			return cfg, fmt.Errorf("reading config file: %v", err)
+		}
+		if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parsing config file %s: %v", *configFile, err)
+		}
+	}
+
+	final := cfg.flagSet()
+	var err error
+	final.VisitAll(func(f *flag.Flag) {
+		env := envPrefix + strings.ToUpper(strings.Replace(f.Name, "-", "_", -1))
+		if v, ok := os.LookupEnv(env); ok && err == nil {
+			if setErr := final.Set(f.Name, v); setErr != nil {
+				err = fmt.Errorf("invalid value %q for %s: %v", v, env, setErr)
+			}
+		}
+	})
+	if err != nil {
+		return cfg, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "config" {
+			final.Set(f.Name, f.Value.String())
+		}
+	})
+
+	return cfg, cfg.validate()
+}
+
+func (cfg config) validate() error {
+	var problems []string
+
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		problems = append(problems, fmt.Sprintf("address %q: %v", cfg.Address, err))
+	}
+	if _, err := newLogger(io.Discard, cfg.LogLevel, cfg.LogFormat); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if cfg.TLS.Enabled {
+		problems = append(problems, checkFile("tls.cert_file", cfg.TLS.CertFile, "when TLS is enabled")...)
+		problems = append(problems, checkFile("tls.key_file", cfg.TLS.KeyFile, "when TLS is enabled")...)
+		if cfg.TLS.ClientCAFile != "" {
+			problems = append(problems, checkFile("tls.client_ca_file", cfg.TLS.ClientCAFile, "")...)
+		}
+	} else if cfg.TLS.ClientCAFile != "" {
+		problems = append(problems, "tls.client_ca_file requires TLS to be enabled")
+	}
+	if cfg.Limits.MaxRecvMsgSize <= 0 {
+		problems = append(problems, fmt.Sprintf("limits.max_recv_msg_size must be positive, got %d", cfg.Limits.MaxRecvMsgSize))
+	}
+	if cfg.Limits.MaxSendMsgSize <= 0 {
+		problems = append(problems, fmt.Sprintf("limits.max_send_msg_size must be positive, got %d", cfg.Limits.MaxSendMsgSize))
+	}
+	if cfg.Limits.MaxConcurrentStreams > math.MaxUint32 {
+		problems = append(problems, fmt.Sprintf("limits.max_concurrent_streams must not exceed %d, got %d", uint32(math.MaxUint32), cfg.Limits.MaxConcurrentStreams))
+	}
+	if cfg.Limits.ConnectionTimeout <= 0 {
+		problems = append(problems, fmt.Sprintf("limits.connection_timeout must be positive, got %v", cfg.Limits.ConnectionTimeout))
+	}
+	if cfg.MetricsAddress != "" {
+		if _, _, err := net.SplitHostPort(cfg.MetricsAddress); err != nil {
+			problems = append(problems, fmt.Sprintf("metrics_address %q: %v", cfg.MetricsAddress, err))
+		}
+	}
+	if cfg.Auth.Enabled {
+		if cfg.Auth.APIKeysFile == "" && len(cfg.Auth.JWT.Keys) == 0 {
+			problems = append(problems, "auth requires auth.api_keys_file or auth.jwt.keys")
+		}
+		if cfg.Auth.APIKeysFile != "" {
+			problems = append(problems, checkFile("auth.api_keys_file", cfg.Auth.APIKeysFile, "")...)
+		}
+		for i, k := range cfg.Auth.JWT.Keys {
+			name := fmt.Sprintf("auth.jwt.keys[%d]", i)
+			if k.File == "" {
+				problems = append(problems, name+".file is required")
+			} else {
+				problems = append(problems, checkFile(name+".file", k.File, "")...)
+			}
+			if k.Algorithm == "" {
+				problems = append(problems, name+".algorithm is required")
+			}
+		}
+	}
+	if cfg.Auth.PolicyFile != "" {
+		problems = append(problems, checkFile("auth.policy_file", cfg.Auth.PolicyFile, "")...)
+	}
+	for i, rule := range cfg.RateLimits {
+		name := fmt.Sprintf("rate_limits[%d]", i)
+		if len(rule.Methods) == 0 {
+			problems = append(problems, name+".methods is required")
+		}
+		for _, pattern := range rule.Methods {
+			if _, err := path.Match(pattern, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.methods %q: %v", name, pattern, err))
+			}
+		}
+		if rule.Rate < 0 || rule.MaxConcurrent < 0 {
+			problems = append(problems, name+": rate and max_concurrent must not be negative")
+		}
+		if rule.Rate > 0 && rule.Burst < 1 {
+			problems = append(problems, fmt.Sprintf("%s.burst must be at least 1 with a rate, got %d", name, rule.Burst))
+		}
+		if rule.Rate == 0 && rule.MaxConcurrent == 0 {
+			problems = append(problems, name+": needs a rate or max_concurrent")
+		}
+	}
+	if cfg.BigNumbers.MaxDigits <= 0 {
+		problems = append(problems, fmt.Sprintf("big_numbers.max_digits must be positive, got %d", cfg.BigNumbers.MaxDigits))
+	}
+	if cfg.BigNumbers.ModPowMaxWork <= 0 {
+		problems = append(problems, fmt.Sprintf("big_numbers.mod_pow_max_work must be positive, got %d", cfg.BigNumbers.ModPowMaxWork))
+	}
+	if cfg.BigNumbers.FactorizeBudget <= 0 {
+		problems = append(problems, fmt.Sprintf("big_numbers.factorize_budget must be positive, got %v", cfg.BigNumbers.FactorizeBudget))
+	}
+	if cfg.Statistics.ExactLimit < 0 {
+		problems = append(problems, fmt.Sprintf("statistics.exact_limit must not be negative, got %d", cfg.Statistics.ExactLimit))
+	}
+	if cfg.Sessions.TTL <= 0 {
+		problems = append(problems, fmt.Sprintf("sessions.ttl must be positive, got %v", cfg.Sessions.TTL))
+	}
+	if cfg.Sessions.MaxSessions <= 0 {
+		problems = append(problems, fmt.Sprintf("sessions.max_sessions must be positive, got %d", cfg.Sessions.MaxSessions))
+	}
+	if cfg.Sessions.MaxVariables <= 0 {
+		problems = append(problems, fmt.Sprintf("sessions.max_variables must be positive, got %d", cfg.Sessions.MaxVariables))
+	}
+	if cfg.Functions.MaxPerTenant <= 0 {
+		problems = append(problems, fmt.Sprintf("functions.max_per_tenant must be positive, got %d", cfg.Functions.MaxPerTenant))
+	}
+	switch cfg.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if _, _, err := net.SplitHostPort(cfg.Tracing.OTLPEndpoint); err != nil {
+			problems = append(problems, fmt.Sprintf("tracing.otlp_endpoint %q: %v", cfg.Tracing.OTLPEndpoint, err))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("tracing.exporter %q: must be none, stdout or otlp", cfg.Tracing.Exporter))
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		problems = append(problems, fmt.Sprintf("tracing.sample_ratio must be between 0 and 1, got %v", cfg.Tracing.SampleRatio))
+	}
+	if cfg.ShutdownDrainDelay < 0 {
+		problems = append(problems, fmt.Sprintf("shutdown_drain_delay must not be negative, got %v", cfg.ShutdownDrainDelay))
+	}
+	if cfg.ShutdownGracePeriod < 0 {
+		problems = append(problems, fmt.Sprintf("shutdown_grace_period must not be negative, got %v", cfg.ShutdownGracePeriod))
+	}
+	if cfg.HealthCheckInterval <= 0 {
+		problems = append(problems, fmt.Sprintf("health_check_interval must be positive, got %v", cfg.HealthCheckInterval))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// checkFile reports a problem if the file setting name is empty or unreadable.
+// why says when the setting is required, such as "when TLS is enabled".
+func checkFile(name, path, why string) []string {
+	if path == "" {
+		return []string{strings.TrimSpace(fmt.Sprintf("%s is required %s", name, why))}
+	}
+	if _, err := os.Stat(path); err != nil {
+		return []string{fmt.Sprintf("%s: %v", name, err)}
+	}
+	return nil
+}
+
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("log_level %q: must be one of debug, info, warn or error", level)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"runtime/debug"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorLocale is the locale of LocalizedMessage details.
+const errorLocale = "en-US"
+
+// requestError describes why a handler rejects a request. Its status carries
+// the details clients need to handle it: a BadRequest violation for the
+// offending field, an ErrorInfo reason programs can switch on, and a
+// LocalizedMessage to show to people.
+type requestError struct {
+	code codes.Code
+	// reason is an UPPER_SNAKE_CASE ErrorInfo reason, e.g. NEGATIVE_NUMBER.
+	reason string
+	// field is the path of the offending request field and description says
+	// what is wrong with it. An empty field adds no BadRequest detail.
+	field       string
+	description string
+	// message is the status message, also sent as the LocalizedMessage.
+	message  string
+	metadata map[string]string
+}
+
+func (e requestError) err() error {
+	st := status.New(e.code, e.message)
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   e.reason,
+			Domain:   calculatorServiceName,
+			Metadata: e.metadata,
+		},
+		&errdetails.LocalizedMessage{
+			Locale:  errorLocale,
+			Message: e.message,
+		},
+	}
+	if e.field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+				Field:       e.field,
+				Description: e.description,
+			}},
+		})
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails.Err()
+	}
+	return st.Err()
+}
+
+// streamError turns an error from stream.Send or stream.Recv into the status
+// a handler should return. A client that canceled or ran out of time is a
+// normal event, not a server failure.
+func streamError(ctx context.Context, err error, action string) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		loggerFromContext(ctx).Debug("Client went away", "action", action, "reason", ctxErr)
+		return status.FromContextError(ctxErr).Err()
+	}
+
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded:
+		loggerFromContext(ctx).Debug("Client went away", "action", action, "reason", err)
+		return err
+	}
+
+	loggerFromContext(ctx).Warn("Stream failed", "action", action, "error", err)
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.Unavailable, "%s: %v", action, err)
+}
+
+// recoverUnaryInterceptor and recoverStreamInterceptor turn a panicking
+// handler into a codes.Internal error instead of crashing the server.
+func recoverUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	defer recoverHandler(ctx, info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+func recoverStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverHandler(ss.Context(), info.FullMethod, &err)
+	return handler(srv, ss)
+}
+
+func recoverHandler(ctx context.Context, method string, err *error) {
+	if r := recover(); r != nil {
+		loggerFromContext(ctx).Error("Handler panicked", "method", method, "panic", r, "stack", string(debug.Stack()))
+		*err = status.Errorf(codes.Internal, "internal error in %s", method)
+	}
+}
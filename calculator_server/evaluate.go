@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+var constants = map[string]float64{
+	"pi":  math.Pi,
+	"e":   math.E,
+	"phi": math.Phi,
+}
+
+// builtin is a function expressions may call. maxArgs < 0 means any number
+// of arguments.
+type builtin struct {
+	minArgs, maxArgs int
+	fn               func(args []float64) float64
+}
+
+func unary(fn func(float64) float64) builtin {
+	return builtin{1, 1, func(args []float64) float64 { return fn(args[0]) }}
+}
+
+func binary(fn func(float64, float64) float64) builtin {
+	return builtin{2, 2, func(args []float64) float64 { return fn(args[0], args[1]) }}
+}
+
+var builtins = map[string]builtin{
+	"sqrt":  unary(math.Sqrt),
+	"cbrt":  unary(math.Cbrt),
+	"abs":   unary(math.Abs),
+	"exp":   unary(math.Exp),
+	"ln":    unary(math.Log),
+	"log10": unary(math.Log10),
+	"log2":  unary(math.Log2),
+	"sin":   unary(math.Sin),
+	"cos":   unary(math.Cos),
+	"tan":   unary(math.Tan),
+	"asin":  unary(math.Asin),
+	"acos":  unary(math.Acos),
+	"atan":  unary(math.Atan),
+	"sinh":  unary(math.Sinh),
+	"cosh":  unary(math.Cosh),
+	"tanh":  unary(math.Tanh),
+	"floor": unary(math.Floor),
+	"ceil":  unary(math.Ceil),
+	"round": unary(math.Round),
+	"trunc": unary(math.Trunc),
+	"atan2": binary(math.Atan2),
+	"hypot": binary(math.Hypot),
+	"pow":   binary(math.Pow),
+	// log(x) is the natural logarithm, log(x, b) the logarithm to base b.
+	"log": {1, 2, func(args []float64) float64 {
+		if len(args) == 1 {
+			return math.Log(args[0])
+		}
+		return math.Log(args[0]) / math.Log(args[1])
+	}},
+	"min": {1, -1, func(args []float64) float64 {
+		m := args[0]
+		for _, x := range args[1:] {
+			m = math.Min(m, x)
+		}
+		return m
+	}},
+	"max": {1, -1, func(args []float64) float64 {
+		m := args[0]
+		for _, x := range args[1:] {
+			m = math.Max(m, x)
+		}
+		return m
+	}},
+}
+
+func (s *server) Evaluate(ctx context.Context, req *calculatorpb.EvaluateRequest) (*calculatorpb.EvaluateResponse, error) {
+	src := req.GetExpression()
+	statements, err := parseProgram(src)
+	if err != nil {
+		return nil, expressionError(src, err)
+	}
+
+	ev := &evaluator{vars: map[string]float64{}}
+	functions := s.functions.functions(ctx)
+	var sess *session
+	if id := req.GetSessionId(); id != "" {
+		if sess, err = s.sessions.get(ctx, id); err != nil {
+			return nil, err
+		}
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		// The session only changes if every statement succeeds.
+		ev = &evaluator{
+			vars:         maps.Clone(sess.vars),
+			ans:          sess.ans,
+			hasAns:       sess.hasAns,
+			maxVariables: s.sessions.cfg.MaxVariables,
+		}
+	}
+	ev.functions = functions
+
+	for _, n := range statements {
+		x, err := ev.eval(n)
+		if err != nil {
+			return nil, expressionError(src, err)
+		}
+		ev.ans, ev.hasAns = x, true
+	}
+	if sess != nil {
+		sess.vars, sess.ans, sess.hasAns = ev.vars, ev.ans, true
+	}
+	return &calculatorpb.EvaluateResponse{Result: ev.ans}, nil
+}
+
+// expressionError turns an *exprError into a status error whose details give
+// the position of the problem as a character count from 1.
+func expressionError(src string, err error) error {
+	return sourceError("expression", "evaluate the expression", src, err)
+}
+
+// definitionError is expressionError for a function definition.
+func definitionError(src string, err error) error {
+	return sourceError("definition", "define the function", src, err)
+}
+
+func sourceError(field, action, src string, err error) error {
+	var e *exprError
+	if !errors.As(err, &e) {
+		return err
+	}
+	position := utf8.RuneCountInString(src[:min(e.pos, len(src))]) + 1
+	if len(src) > maxExpressionLength {
+		src = src[:maxExpressionLength]
+	}
+	return requestError{
+		code:        e.code,
+		reason:      e.reason,
+		field:       field,
+		description: fmt.Sprintf("%s at position %d", e.message, position),
+		message:     fmt.Sprintf("Cannot %s: %s at position %d", action, e.message, position),
+		metadata:    map[string]string{field: src, "position": strconv.Itoa(position)},
+	}.err()
+}
+
+// evaluator evaluates parsed expressions.
+type evaluator struct {
+	// vars holds the variables, including those assigned so far.
+	vars map[string]float64
+	// ans is the result of the previous statement.
+	ans    float64
+	hasAns bool
+	// maxVariables bounds len(vars) if positive.
+	maxVariables int
+	// functions are the user-defined functions of the caller's tenant.
+	functions map[string]*userFunction
+	// depth counts the calls of user-defined functions in progress, steps
+	// the nodes visited.
+	depth, steps int
+}
+
+// eval evaluates n, counting the step against maxEvaluationSteps.
+func (ev *evaluator) eval(n node) (float64, error) {
+	if ev.steps++; ev.steps > maxEvaluationSteps {
+		return 0, &exprError{codes.ResourceExhausted, "EVALUATION_TOO_LONG", 0,
+			fmt.Sprintf("the evaluation takes more than %d steps", maxEvaluationSteps)}
+	}
+	return n.eval(ev)
+}
+
+func (n *numberNode) eval(ev *evaluator) (float64, error) {
+	return n.value, nil
+}
+
+func (n *identNode) eval(ev *evaluator) (float64, error) {
+	if value, ok := ev.vars[n.name]; ok {
+		return value, nil
+	}
+	if n.name == "ans" {
+		if !ev.hasAns {
+			return 0, errorAt(n.pos, "NO_PREVIOUS_RESULT", "ans is the previous result, and there is none yet")
+		}
+		return ev.ans, nil
+	}
+	if value, ok := constants[n.name]; ok {
+		return value, nil
+	}
+	if _, ok := builtins[n.name]; ok {
+		return 0, errorAt(n.pos, "MISSING_ARGUMENTS", "%s is a function and needs arguments in parentheses", n.name)
+	}
+	return 0, errorAt(n.pos, "UNKNOWN_NAME", "unknown name %q", n.name)
+}
+
+func (n *unaryNode) eval(ev *evaluator) (float64, error) {
+	x, err := ev.eval(n.operand)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "-" {
+		return -x, nil
+	}
+	return x, nil
+}
+
+func (n *binaryNode) eval(ev *evaluator) (float64, error) {
+	x, err := ev.eval(n.left)
+	if err != nil {
+		return 0, err
+	}
+	y, err := ev.eval(n.right)
+	if err != nil {
+		return 0, err
+	}
+
+	var result float64
+	switch n.op {
+	case "+":
+		result = x + y
+	case "-":
+		result = x - y
+	case "*":
+		result = x * y
+	case "/", "%":
+		if y == 0 {
+			return 0, errorAt(n.pos, "DIVISION_BY_ZERO", "division by zero")
+		}
+		if n.op == "/" {
+			result = x / y
+		} else {
+			result = math.Mod(x, y)
+		}
+	case "^":
+		result = math.Pow(x, y)
+	}
+	return checkFinite(result, n.pos, func() string {
+		return formatNumber(x) + " " + n.op + " " + formatNumber(y)
+	})
+}
+
+func (n *callNode) eval(ev *evaluator) (float64, error) {
+	f, ok := builtins[n.name]
+	if !ok {
+		if g, ok := ev.functions[n.name]; ok {
+			return ev.call(g, n)
+		}
+		if _, ok := constants[n.name]; ok {
+			return 0, errorAt(n.pos, "NOT_A_FUNCTION", "%s is a constant, not a function", n.name)
+		}
+		return 0, errorAt(n.pos, "UNKNOWN_FUNCTION", "unknown function %q", n.name)
+	}
+	if len(n.args) < f.minArgs || f.maxArgs >= 0 && len(n.args) > f.maxArgs {
+		return 0, errorAt(n.pos, "WRONG_ARGUMENT_COUNT", "%s takes %s, got %d", n.name, arity(f), len(n.args))
+	}
+
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		x, err := ev.eval(arg)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = x
+	}
+	return checkFinite(f.fn(args), n.pos, func() string {
+		formatted := make([]string, len(args))
+		for i, x := range args {
+			formatted[i] = formatNumber(x)
+		}
+		return n.name + "(" + strings.Join(formatted, ", ") + ")"
+	})
+}
+
+// call calls the user-defined function f. Its body only sees the arguments,
+// and errors in it are reported at the call.
+func (ev *evaluator) call(f *userFunction, n *callNode) (float64, error) {
+	if len(n.args) != len(f.params) {
+		return 0, errorAt(n.pos, "WRONG_ARGUMENT_COUNT", "%s takes %s, got %d", n.name, arguments(len(f.params)), len(n.args))
+	}
+	if ev.depth >= maxCallDepth {
+		return 0, &exprError{codes.ResourceExhausted, "CALL_TOO_DEEP", n.pos,
+			fmt.Sprintf("functions call each other more than %d levels deep", maxCallDepth)}
+	}
+
+	frame := make(map[string]float64, len(f.params))
+	for i, arg := range n.args {
+		x, err := ev.eval(arg)
+		if err != nil {
+			return 0, err
+		}
+		frame[f.params[i]] = x
+	}
+
+	vars := ev.vars
+	ev.vars = frame
+	ev.depth++
+	x, err := ev.eval(f.body)
+	ev.vars = vars
+	ev.depth--
+
+	var e *exprError
+	switch {
+	case !errors.As(err, &e):
+		return x, err
+	case e.code == codes.ResourceExhausted:
+		// The limits are on the evaluation as a whole.
+		return 0, &exprError{e.code, e.reason, n.pos, e.message}
+	}
+	return 0, &exprError{e.code, e.reason, n.pos, "in " + f.name + ": " + e.message}
+}
+
+func (n *assignNode) eval(ev *evaluator) (float64, error) {
+	if isReserved(n.name) {
+		return 0, errorAt(n.pos, "RESERVED_NAME", "%s is built in and cannot be assigned", n.name)
+	}
+	if _, ok := ev.vars[n.name]; !ok && ev.maxVariables > 0 && len(ev.vars) >= ev.maxVariables {
+		return 0, &exprError{codes.ResourceExhausted, "TOO_MANY_VARIABLES", n.pos,
+			fmt.Sprintf("cannot assign %s: the session already has %d variables", n.name, ev.maxVariables)}
+	}
+
+	x, err := ev.eval(n.value)
+	if err != nil {
+		return 0, err
+	}
+	ev.vars[n.name] = x
+	return x, nil
+}
+
+func arity(f builtin) string {
+	switch {
+	case f.maxArgs < 0:
+		return "at least " + arguments(f.minArgs)
+	case f.minArgs == f.maxArgs:
+		return arguments(f.minArgs)
+	}
+	return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+}
+
+func arguments(n int) string {
+	if n == 1 {
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d arguments", n)
+}
+
+// checkFinite rejects a result that is not a number or infinite. Every value
+// an expression starts from is finite, so the operation at pos, described by
+// what, caused it.
+func checkFinite(result float64, pos int, what func() string) (float64, error) {
+	if math.IsNaN(result) {
+		return 0, errorAt(pos, "DOMAIN_ERROR", "%s is undefined", what())
+	}
+	if math.IsInf(result, 0) {
+		return 0, &exprError{codes.OutOfRange, "RESULT_OUT_OF_RANGE", pos, what() + " is out of range"}
+	}
+	return result, nil
+}
+
+func formatNumber(x float64) string {
+	return strconv.FormatFloat(x, 'g', 6, 64)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestServer returns a server with the default configuration.
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+	cfg := defaultConfig()
+	functions, err := newFunctionStore(cfg.Functions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &server{
+		big:       cfg.BigNumbers,
+		stats:     cfg.Statistics,
+		sessions:  newSessionStore(cfg.Sessions),
+		functions: functions,
+	}
+}
+
+// errorInfo returns the code of err and the reason and metadata of its
+// ErrorInfo detail.
+func errorInfo(err error) (codes.Code, string, map[string]string) {
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return st.Code(), info.GetReason(), info.GetMetadata()
+		}
+	}
+	return st.Code(), "", nil
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"64 / 8 / 2", 4},
+		{"7 % 4 * 2", 6},
+		{"2 ^ 3 ^ 2", 512},
+		{"(2 ^ 3) ^ 2", 64},
+		{"2 * 3 ^ 2", 18},
+		{"-2 ^ 2", -4},
+		{"(-2) ^ 2", 4},
+		{"2 ^ -1", 0.5},
+		{"--3", 3},
+		{"+-3", -3},
+		{"2 * -3", -6},
+		{"1 - -1", 2},
+		{"sqrt(16) + abs(-2)", 6},
+		{"max(1, 5, 3) - min(4, 2)", 3},
+		{"log(8, 2)", 3},
+		{"2 * pi", 2 * math.Pi},
+		{"x = 5; y = x * 2; x + y", 15},
+		{"3; ans * ans", 9},
+		{"1.5e3 + .5", 1500.5},
+	}
+	s := newTestServer(t)
+	for _, tt := range tests {
+		res, err := s.Evaluate(context.Background(), &calculatorpb.EvaluateRequest{Expression: tt.expression})
+		if err != nil {
+			t.Errorf("Evaluate(%q) failed: %v", tt.expression, err)
+			continue
+		}
+		if got := res.GetResult(); math.Abs(got-tt.want) > 1e-12*math.Abs(tt.want) {
+			t.Errorf("Evaluate(%q) = %v, want %v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		code       codes.Code
+		reason     string
+		// position counts characters from 1.
+		position string
+	}{
+		{"1 / 0", codes.InvalidArgument, "DIVISION_BY_ZERO", "3"},
+		{"5 % (2 - 2)", codes.InvalidArgument, "DIVISION_BY_ZERO", "3"},
+		{"sqrt(-1)", codes.InvalidArgument, "DOMAIN_ERROR", "1"},
+		{"10 ^ 400", codes.OutOfRange, "RESULT_OUT_OF_RANGE", "4"},
+		{"ans + 1", codes.InvalidArgument, "NO_PREVIOUS_RESULT", "1"},
+		{"1 + y", codes.InvalidArgument, "UNKNOWN_NAME", "5"},
+		{"2 * sqrt", codes.InvalidArgument, "MISSING_ARGUMENTS", "5"},
+		{"pi(2)", codes.InvalidArgument, "NOT_A_FUNCTION", "1"},
+		{"f(2)", codes.InvalidArgument, "UNKNOWN_FUNCTION", "1"},
+		{"atan2(1)", codes.InvalidArgument, "WRONG_ARGUMENT_COUNT", "1"},
+		{"x = 1; pi = 3", codes.InvalidArgument, "RESERVED_NAME", "8"},
+		{"(1 + 2", codes.InvalidArgument, "UNBALANCED_PARENTHESES", "1"},
+		// Positions count characters, not bytes: é, π and ö take two.
+		{"é = 1; é + 1 / 0", codes.InvalidArgument, "DIVISION_BY_ZERO", "14"},
+		{"π ÷ 2", codes.InvalidArgument, "UNEXPECTED_CHARACTER", "3"},
+		{"1 € 2", codes.InvalidArgument, "UNEXPECTED_CHARACTER", "3"},
+		{"ö ö", codes.InvalidArgument, "UNEXPECTED_TOKEN", "3"},
+	}
+	s := newTestServer(t)
+	for _, tt := range tests {
+		_, err := s.Evaluate(context.Background(), &calculatorpb.EvaluateRequest{Expression: tt.expression})
+		code, reason, metadata := errorInfo(err)
+		if code != tt.code || reason != tt.reason || metadata["position"] != tt.position {
+			t.Errorf("Evaluate(%q) fails with %v %s at %s (%v), want %v %s at %s",
+				tt.expression, code, reason, metadata["position"], err, tt.code, tt.reason, tt.position)
+		}
+	}
+}
+
+func TestEvaluateSteps(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	// f<n>(x) is 2^(2^(n-1)) * x and visits about 2^n nodes.
+	if _, _, err := s.functions.define(ctx, "f1(x) = x + x", false); err != nil {
+		t.Fatal(err)
+	}
+	for n := 2; n <= 20; n++ {
+		if _, _, err := s.functions.define(ctx, fmt.Sprintf("f%d(x) = f%d(f%d(x))", n, n-1, n-1), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := s.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "f10(1)"})
+	if want := math.Ldexp(1, 1<<9); err != nil || res.GetResult() != want {
+		t.Errorf("Evaluate(f10(1)) = %v, %v, want %v", res.GetResult(), err, want)
+	}
+
+	_, err = s.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "1 + f20(0)"})
+	code, reason, metadata := errorInfo(err)
+	if code != codes.ResourceExhausted || reason != "EVALUATION_TOO_LONG" || metadata["position"] != "5" {
+		t.Errorf("Evaluate(1 + f20(0)) fails with %v %s at %s (%v), want %v EVALUATION_TOO_LONG at 5",
+			code, reason, metadata["position"], err, codes.ResourceExhausted)
+	}
+}
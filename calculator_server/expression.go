@@ -0,0 +1,500 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// maxExpressionLength bounds the size in bytes of an expression.
+	maxExpressionLength = 4096
+	// maxExpressionDepth bounds the nesting of parentheses, function calls
+	// and unary operators.
+	maxExpressionDepth = 100
+	// maxNameLength bounds the length in bytes of a variable name.
+	maxNameLength = 64
+)
+
+// exprError is an error at a position of an expression. pos is a byte offset.
+type exprError struct {
+	code    codes.Code
+	reason  string
+	pos     int
+	message string
+}
+
+func (e *exprError) Error() string {
+	return e.message
+}
+
+func errorAt(pos int, reason, format string, a ...interface{}) *exprError {
+	return &exprError{codes.InvalidArgument, reason, pos, fmt.Sprintf(format, a...)}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+	tokenAssign
+	tokenSemicolon
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	pos   int
+	value float64
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// tokenize splits an expression into tokens, ending with a tokenEOF.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(src); {
+		r, size := utf8.DecodeRuneInString(src[pos:])
+		start := pos
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+			continue
+		case r >= '0' && r <= '9' || r == '.':
+			pos = scanNumber(src, pos)
+			value, err := strconv.ParseFloat(src[start:pos], 64)
+			if err != nil {
+				return nil, errorAt(start, "INVALID_NUMBER", "invalid number %q", src[start:pos])
+			}
+			tokens = append(tokens, token{tokenNumber, src[start:pos], start, value})
+			continue
+		case r == '_' || unicode.IsLetter(r):
+			for pos < len(src) {
+				r, size := utf8.DecodeRuneInString(src[pos:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				pos += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[start:pos], pos: start})
+			continue
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: start})
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: start})
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: start})
+		case r == '=':
+			tokens = append(tokens, token{kind: tokenAssign, text: "=", pos: start})
+		case r == ';':
+			tokens = append(tokens, token{kind: tokenSemicolon, text: ";", pos: start})
+		case r == '+' || r == '-' || r == '*' || r == '/' || r == '%' || r == '^':
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: start})
+		default:
+			return nil, errorAt(start, "UNEXPECTED_CHARACTER", "unexpected character %q", r)
+		}
+		pos += size
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// scanNumber returns the end of the number starting at pos: digits with an
+// optional fraction and exponent, such as 12, .5 or 1.5e-3.
+func scanNumber(src string, pos int) int {
+	digits := func() {
+		for pos < len(src) && src[pos] >= '0' && src[pos] <= '9' {
+			pos++
+		}
+	}
+	digits()
+	if pos < len(src) && src[pos] == '.' {
+		pos++
+		digits()
+	}
+	if pos < len(src) && (src[pos] == 'e' || src[pos] == 'E') {
+		end := pos + 1
+		if end < len(src) && (src[end] == '+' || src[end] == '-') {
+			end++
+		}
+		if end < len(src) && src[end] >= '0' && src[end] <= '9' {
+			pos = end
+			digits()
+		}
+	}
+	return pos
+}
+
+// node is a parsed expression.
+type node interface {
+	eval(ev *evaluator) (float64, error)
+}
+
+type numberNode struct {
+	value float64
+}
+
+type identNode struct {
+	name string
+	pos  int
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+	pos     int
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+	pos         int
+}
+
+type callNode struct {
+	name string
+	args []node
+	pos  int
+}
+
+type assignNode struct {
+	name  string
+	value node
+	pos   int
+}
+
+// definition is a parsed function definition such as f(x, y) = x^2 + y.
+type definition struct {
+	src      string
+	name     string
+	pos      int
+	params   []string
+	paramPos []int
+	body     node
+	// bodySrc is the source of body.
+	bodySrc string
+}
+
+// parser is a recursive descent parser for the grammar
+//
+//	program   = statement { ";" statement }
+//	statement = [ ident "=" ] expr | empty
+//	expr      = term { ("+" | "-") term }
+//	term      = unary { ("*" | "/" | "%") unary }
+//	unary     = ("+" | "-") unary | power
+//	power     = primary [ "^" unary ]
+//	primary   = number | ident [ "(" [ expr { "," expr } ] ")" ] | "(" expr ")"
+//
+// so ^ binds tighter than unary minus and is right-associative: -2^2 is -4
+// and 2^3^2 is 2^9. Function definitions are
+//
+//	definition = ident "(" [ ident { "," ident } ] ")" "=" expr
+type parser struct {
+	tokens []token
+	next   int
+	depth  int
+}
+
+// parseProgram parses src into its statements, leaving out empty ones.
+func parseProgram(src string) ([]node, error) {
+	if len(src) > maxExpressionLength {
+		return nil, errorAt(maxExpressionLength, "EXPRESSION_TOO_LONG",
+			"the expression has %d bytes, at most %d are allowed", len(src), maxExpressionLength)
+	}
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	var statements []node
+	for {
+		if k := p.peek().kind; k != tokenSemicolon && k != tokenEOF {
+			n, err := p.statement()
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, n)
+		}
+		switch t := p.advance(); t.kind {
+		case tokenSemicolon:
+		case tokenEOF:
+			if len(statements) == 0 {
+				return nil, errorAt(0, "EMPTY_EXPRESSION", "the expression is empty")
+			}
+			return statements, nil
+		default:
+			return nil, p.unexpected(t, "an operator")
+		}
+	}
+}
+
+// parseDefinition parses a function definition.
+func parseDefinition(src string) (*definition, error) {
+	if len(src) > maxExpressionLength {
+		return nil, errorAt(maxExpressionLength, "EXPRESSION_TOO_LONG",
+			"the definition has %d bytes, at most %d are allowed", len(src), maxExpressionLength)
+	}
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	d := &definition{src: src, name: name.text, pos: name.pos}
+	if t := p.advance(); t.kind != tokenLeftParen {
+		return nil, p.unexpected(t, "( and the parameters")
+	}
+	if p.peek().kind == tokenRightParen {
+		p.advance()
+	} else {
+		for {
+			param, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			d.params = append(d.params, param.text)
+			d.paramPos = append(d.paramPos, param.pos)
+			t := p.advance()
+			if t.kind == tokenRightParen {
+				break
+			}
+			if t.kind != tokenComma {
+				return nil, p.unexpected(t, ", or )")
+			}
+		}
+	}
+	if t := p.advance(); t.kind != tokenAssign {
+		return nil, p.unexpected(t, "=")
+	}
+
+	start := p.peek().pos
+	if d.body, err = p.expr(); err != nil {
+		return nil, err
+	}
+	if t := p.advance(); t.kind != tokenEOF {
+		return nil, p.unexpected(t, "an operator")
+	}
+	d.bodySrc = strings.TrimSpace(src[start:])
+	return d, nil
+}
+
+// name parses a variable, function or parameter name.
+func (p *parser) name() (token, error) {
+	t := p.advance()
+	if t.kind != tokenIdent {
+		return t, p.unexpected(t, "a name")
+	}
+	if len(t.text) > maxNameLength {
+		return t, errorAt(t.pos, "NAME_TOO_LONG", "the name %.20q... is longer than %d bytes", t.text, maxNameLength)
+	}
+	return t, nil
+}
+
+func (p *parser) statement() (node, error) {
+	name := p.peek()
+	if name.kind != tokenIdent || p.tokens[p.next+1].kind != tokenAssign {
+		return p.expr()
+	}
+	if _, err := p.name(); err != nil {
+		return nil, err
+	}
+	p.advance()
+	value, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return &assignNode{name.text, value, name.pos}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) unexpected(t token, expected string) error {
+	if t.kind == tokenRightParen && expected == "an operator" {
+		return errorAt(t.pos, "UNBALANCED_PARENTHESES", "unexpected %s without a matching (", t)
+	}
+	return errorAt(t.pos, "UNEXPECTED_TOKEN", "unexpected %s, expected %s", t, expected)
+}
+
+// enter guards against expressions nested so deeply that they would exhaust
+// the stack.
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxExpressionDepth {
+		return errorAt(p.peek().pos, "EXPRESSION_TOO_DEEP", "the expression is nested more than %d levels deep", maxExpressionDepth)
+	}
+	return nil
+}
+
+func (p *parser) expr() (node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.advance()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op.text, left, right, op.pos}
+	}
+	return left, nil
+}
+
+func (p *parser) term() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/", "%") {
+		op := p.advance()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op.text, left, right, op.pos}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if !p.isOperator("+", "-") {
+		return p.power()
+	}
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	op := p.advance()
+	operand, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return &unaryNode{op.text, operand, op.pos}, nil
+}
+
+func (p *parser) power() (node, error) {
+	base, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("^") {
+		return base, nil
+	}
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	op := p.advance()
+	exponent, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op.text, base, exponent, op.pos}, nil
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokenNumber:
+		return &numberNode{t.value}, nil
+	case tokenIdent:
+		if p.peek().kind != tokenLeftParen {
+			return &identNode{t.text, t.pos}, nil
+		}
+		p.advance()
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		return &callNode{t.text, args, t.pos}, nil
+	case tokenLeftParen:
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer func() { p.depth-- }()
+
+		n, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenRightParen {
+			if closing.kind == tokenEOF {
+				return nil, errorAt(t.pos, "UNBALANCED_PARENTHESES", "( is never closed")
+			}
+			return nil, p.unexpected(closing, "an operator or )")
+		}
+		return n, nil
+	}
+	return nil, p.unexpected(t, "a number, name or (")
+}
+
+// arguments parses the arguments of a call after its (.
+func (p *parser) arguments() ([]node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	var args []node
+	if p.peek().kind == tokenRightParen {
+		p.advance()
+		return args, nil
+	}
+	for {
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		switch t := p.advance(); t.kind {
+		case tokenComma:
+		case tokenRightParen:
+			return args, nil
+		default:
+			return nil, p.unexpected(t, "an operator, , or )")
+		}
+	}
+}
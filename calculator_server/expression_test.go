@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseProgram(t *testing.T) {
+	tests := []struct {
+		src        string
+		statements int
+	}{
+		{"1 + 2 * 3", 1},
+		{"x = 5; x * 2", 2},
+		{"; x = 1;; x;", 2},
+		{"1.5e-3 + .5 + 2.", 1},
+		{"-(-(1))", 1},
+		{strings.Repeat("(", maxExpressionDepth) + "1" + strings.Repeat(")", maxExpressionDepth), 1},
+		{strings.Repeat("-", maxExpressionDepth) + "1", 1},
+	}
+	for _, tt := range tests {
+		statements, err := parseProgram(tt.src)
+		if err != nil {
+			t.Errorf("parseProgram(%.40q) failed: %v", tt.src, err)
+			continue
+		}
+		if len(statements) != tt.statements {
+			t.Errorf("parseProgram(%.40q) has %d statements, want %d", tt.src, len(statements), tt.statements)
+		}
+	}
+}
+
+func TestParseProgramErrors(t *testing.T) {
+	deep := strings.Repeat("(", maxExpressionDepth+1) + "1" + strings.Repeat(")", maxExpressionDepth+1)
+	tests := []struct {
+		src    string
+		reason string
+		// pos is the byte offset of the error.
+		pos int
+	}{
+		{"", "EMPTY_EXPRESSION", 0},
+		{" ; ", "EMPTY_EXPRESSION", 0},
+		{"1 +", "UNEXPECTED_TOKEN", 3},
+		{"1 2", "UNEXPECTED_TOKEN", 2},
+		{"1..2", "UNEXPECTED_TOKEN", 2},
+		{"* 2", "UNEXPECTED_TOKEN", 0},
+		{"f(1,)", "UNEXPECTED_TOKEN", 4},
+		{"(1 + 2", "UNBALANCED_PARENTHESES", 0},
+		{"2 * (1 + (3)", "UNBALANCED_PARENTHESES", 4},
+		{"1 + 2)", "UNBALANCED_PARENTHESES", 5},
+		{"2 $ 3", "UNEXPECTED_CHARACTER", 2},
+		// Positions are byte offsets here: é takes two bytes.
+		{"é € 3", "UNEXPECTED_CHARACTER", 3},
+		{strings.Repeat("x", maxNameLength+1) + " = 1", "NAME_TOO_LONG", 0},
+		{deep, "EXPRESSION_TOO_DEEP", maxExpressionDepth + 1},
+		{strings.Repeat("-", maxExpressionDepth+1) + "1", "EXPRESSION_TOO_DEEP", maxExpressionDepth},
+		{strings.Repeat("2^", maxExpressionDepth+1) + "2", "EXPRESSION_TOO_DEEP", 2*maxExpressionDepth + 1},
+		{strings.Repeat("1+", maxExpressionLength/2) + "1", "EXPRESSION_TOO_LONG", maxExpressionLength},
+	}
+	for _, tt := range tests {
+		_, err := parseProgram(tt.src)
+		var e *exprError
+		if !errors.As(err, &e) {
+			t.Errorf("parseProgram(%.40q) = %v, want a %s error", tt.src, err, tt.reason)
+			continue
+		}
+		if e.reason != tt.reason || e.pos != tt.pos {
+			t.Errorf("parseProgram(%.40q) fails with %s at %d (%v), want %s at %d", tt.src, e.reason, e.pos, e, tt.reason, tt.pos)
+		}
+	}
+}
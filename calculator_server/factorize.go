@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"time"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// progressInterval is how often Factorize reports progress while it looks
+// for the next factor.
+const progressInterval = time.Second
+
+// deadlineMargin is how long before the deadline of the call Factorize stops
+// working, so that the factors found so far still reach the client.
+const deadlineMargin = 200 * time.Millisecond
+
+// errBudgetExhausted stops a factorization whose work budget ran out.
+var errBudgetExhausted = errors.New("work budget exhausted")
+
+var bigOne = big.NewInt(1)
+
+func (s *server) Factorize(req *calculatorpb.FactorizeRequest, stream calculatorpb.CalculatorService_FactorizeServer) error {
+	n, err := s.parseBigInt("number", req.GetNumber())
+	if err != nil {
+		return err
+	}
+	if n.Sign() <= 0 {
+		return requestError{
+			code:        codes.InvalidArgument,
+			reason:      "NUMBER_NOT_POSITIVE",
+			field:       "number",
+			description: "must be positive",
+			message:     fmt.Sprintf("Cannot factorize %v: only positive numbers have prime factors", n),
+		}.err()
+	}
+
+	budget := s.big.FactorizeBudget
+	if b := time.Duration(req.GetBudgetMs()) * time.Millisecond; b > 0 && b < budget {
+		budget = b
+	}
+
+	ctx := stream.Context()
+	now := time.Now()
+	deadline := now.Add(budget)
+	if d, ok := ctx.Deadline(); ok && d.Add(-deadlineMargin).Before(deadline) {
+		deadline = d.Add(-deadlineMargin)
+	}
+	f := &bigFactorizer{
+		ctx:          ctx,
+		deadline:     deadline,
+		lastProgress: now,
+	}
+	if n.Cmp(bigOne) != 0 {
+		f.work = []*big.Int{n}
+	}
+	f.send = func(res *calculatorpb.FactorizeResponse) error {
+		res.Steps = f.steps
+		res.RemainingDigits = f.remainingDigits()
+		f.lastProgress = time.Now()
+		if err := stream.Send(res); err != nil {
+			return streamError(ctx, err, "sending factorization")
+		}
+		return nil
+	}
+
+	err = f.factorize()
+	if err != nil && err != errBudgetExhausted {
+		return err
+	}
+	if err == errBudgetExhausted {
+		loggerFromContext(ctx).Info("Factorization budget exhausted",
+			"budget", budget, "steps", f.steps, "remaining_digits", f.remainingDigits())
+	}
+	return f.send(&calculatorpb.FactorizeResponse{
+		Done:       true,
+		Incomplete: err == errBudgetExhausted,
+		Unfactored: f.unfactored(),
+	})
+}
+
+// bigFactorizer factors an arbitrarily large number within a work budget,
+// sending each prime with its exponent as soon as it is known.
+type bigFactorizer struct {
+	ctx      context.Context
+	deadline time.Time
+	send     func(*calculatorpb.FactorizeResponse) error
+
+	// work holds the parts of the number that are not broken down into
+	// primes yet; their product is what is left to factor.
+	work         []*big.Int
+	steps        uint64
+	lastProgress time.Time
+}
+
+func (f *bigFactorizer) factorize() error {
+	p, square := new(big.Int), new(big.Int)
+	for i, small := range smallPrimes {
+		if i%rhoBatch == 0 {
+			if err := f.tick(min(rhoBatch, len(smallPrimes)-i)); err != nil {
+				return err
+			}
+		}
+		if len(f.work) == 0 || square.Mul(p.SetUint64(small), p).Cmp(f.work[0]) > 0 {
+			break
+		}
+		if err := f.found(p); err != nil {
+			return err
+		}
+	}
+
+	for len(f.work) > 0 {
+		// Smaller parts are quicker to factor.
+		i := 0
+		for j, c := range f.work {
+			if c.BitLen() < f.work[i].BitLen() {
+				i = j
+			}
+		}
+		c := f.work[i]
+		if isProbablePrime(c) {
+			if err := f.found(new(big.Int).Set(c)); err != nil {
+				return err
+			}
+			continue
+		}
+		// Pollard's rho cannot split the power of a large prime.
+		if r, k := perfectPower(c); k > 1 {
+			f.work[i] = r
+			for j := 1; j < k; j++ {
+				f.work = append(f.work, new(big.Int).Set(r))
+			}
+			continue
+		}
+		d, err := f.rho(c)
+		if err != nil {
+			return err
+		}
+		f.work[i] = d
+		f.work = append(f.work, new(big.Int).Quo(c, d))
+	}
+	return nil
+}
+
+// found divides every power of the prime p out of the remaining parts and
+// sends p with its exponent if it divided any of them.
+func (f *bigFactorizer) found(p *big.Int) error {
+	var exponent uint32
+	q, r := new(big.Int), new(big.Int)
+	work := f.work[:0]
+	for _, c := range f.work {
+		for {
+			q.QuoRem(c, p, r)
+			if r.Sign() != 0 {
+				break
+			}
+			c.Set(q)
+			exponent++
+		}
+		if c.Cmp(bigOne) != 0 {
+			work = append(work, c)
+		}
+	}
+	f.work = work
+	if exponent == 0 {
+		return nil
+	}
+
+	trace.SpanFromContext(f.ctx).AddEvent("prime factor", trace.WithAttributes(
+		attribute.String("factor", p.String()),
+		attribute.Int("exponent", int(exponent))))
+	return f.send(&calculatorpb.FactorizeResponse{
+		Prime: &calculatorpb.FactorPower{Factor: p.String(), Exponent: exponent},
+	})
+}
+
+// tick accounts for steps of work. It stops the factorization if the client
+// went away or the budget ran out, and reports progress every
+// progressInterval.
+func (f *bigFactorizer) tick(steps int) error {
+	f.steps += uint64(steps)
+	if err := f.ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	now := time.Now()
+	if now.After(f.deadline) {
+		return errBudgetExhausted
+	}
+	if now.Sub(f.lastProgress) >= progressInterval {
+		return f.send(&calculatorpb.FactorizeResponse{})
+	}
+	return nil
+}
+
+// rho returns a non-trivial factor of the odd composite n, using Brent's
+// variant of Pollard's rho algorithm like pollardRho does for 64-bit numbers.
+func (f *bigFactorizer) rho(n *big.Int) (*big.Int, error) {
+	if n.IsUint64() {
+		d, err := pollardRho(n.Uint64(), f.tick)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetUint64(d), nil
+	}
+
+	x, y, ys, q, g, diff := new(big.Int), new(big.Int), new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	for c := big.NewInt(1); ; c.Add(c, bigOne) {
+		next := func(z *big.Int) {
+			z.Mul(z, z)
+			z.Add(z, c)
+			z.Mod(z, n)
+		}
+
+		y.SetInt64(2)
+		q.SetInt64(1)
+		g.SetInt64(1)
+		for r := 1; g.Cmp(bigOne) == 0; r *= 2 {
+			x.Set(y)
+			for i := 0; i < r; i++ {
+				if i%rhoBatch == 0 {
+					if err := f.tick(min(rhoBatch, r-i)); err != nil {
+						return nil, err
+					}
+				}
+				next(y)
+			}
+			for k := 0; k < r && g.Cmp(bigOne) == 0; k += rhoBatch {
+				if err := f.tick(min(rhoBatch, r-k)); err != nil {
+					return nil, err
+				}
+				ys.Set(y)
+				for i := 0; i < rhoBatch && i < r-k; i++ {
+					next(y)
+					q.Mul(q, diff.Abs(diff.Sub(x, y)))
+					q.Mod(q, n)
+				}
+				g.GCD(nil, nil, q, n)
+			}
+		}
+
+		// The batch overshot: retrace it one step at a time.
+		if g.Cmp(n) == 0 {
+			for g.SetInt64(1); g.Cmp(bigOne) == 0; {
+				next(ys)
+				g.GCD(nil, nil, diff.Abs(diff.Sub(x, ys)), n)
+			}
+		}
+		if g.Cmp(n) != 0 {
+			return new(big.Int).Set(g), nil
+		}
+	}
+}
+
+// isProbablePrime reports whether n is prime: exactly for 64-bit numbers and
+// with the Baillie-PSW test, which has no known counterexample, above.
+func isProbablePrime(n *big.Int) bool {
+	if n.IsUint64() {
+		return isPrime(n.Uint64())
+	}
+	return n.ProbablyPrime(0)
+}
+
+// perfectPower returns r and k > 1 with r^k = n if there are any, else n and
+// 1. n must not have a prime factor below smallPrimeLimit, which bounds k.
+func perfectPower(n *big.Int) (*big.Int, int) {
+	maxK := n.BitLen() / bits.Len(smallPrimeLimit-1)
+	for _, p := range smallPrimes {
+		k := int(p)
+		if k > maxK {
+			break
+		}
+		r := root(n, k)
+		if new(big.Int).Exp(r, big.NewInt(int64(k)), nil).Cmp(n) == 0 {
+			return r, k
+		}
+	}
+	return n, 1
+}
+
+// root returns the k-th root of n rounded down, using Newton's method from
+// above.
+func root(n *big.Int, k int) *big.Int {
+	if k == 2 {
+		return new(big.Int).Sqrt(n)
+	}
+	bk, bk1 := big.NewInt(int64(k)), big.NewInt(int64(k-1))
+	x := new(big.Int).Lsh(bigOne, uint((n.BitLen()+k-1)/k))
+	y := new(big.Int)
+	for {
+		// y = ((k-1)x + n/x^(k-1)) / k
+		y.Exp(x, bk1, nil)
+		y.Quo(n, y)
+		y.Add(y, new(big.Int).Mul(x, bk1))
+		y.Quo(y, bk)
+		if y.Cmp(x) >= 0 {
+			return x
+		}
+		x.Set(y)
+	}
+}
+
+// remainingDigits estimates the number of decimal digits of what is left to
+// factor.
+func (f *bigFactorizer) remainingDigits() uint32 {
+	bits := 0
+	for _, c := range f.work {
+		bits += c.BitLen()
+	}
+	return uint32(math.Ceil(float64(bits) / bitsPerDigit))
+}
+
+// unfactored returns the parts that are left, equal parts merged into one
+// with an exponent.
+func (f *bigFactorizer) unfactored() []*calculatorpb.FactorPower {
+	var parts []*calculatorpb.FactorPower
+	seen := map[string]*calculatorpb.FactorPower{}
+	for _, c := range f.work {
+		s := c.String()
+		if part, ok := seen[s]; ok {
+			part.Exponent++
+			continue
+		}
+		part := &calculatorpb.FactorPower{Factor: s, Exponent: 1}
+		seen[s] = part
+		parts = append(parts, part)
+	}
+	return parts
+}
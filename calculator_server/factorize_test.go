@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestFactorizer returns a factorizer of n with the given budget and the
+// map its primes are sent to.
+func newTestFactorizer(ctx context.Context, n *big.Int, budget time.Duration) (*bigFactorizer, map[string]uint32) {
+	primes := map[string]uint32{}
+	f := &bigFactorizer{
+		ctx:          ctx,
+		deadline:     time.Now().Add(budget),
+		lastProgress: time.Now(),
+		work:         []*big.Int{n},
+	}
+	f.send = func(res *calculatorpb.FactorizeResponse) error {
+		if p := res.GetPrime(); p != nil {
+			primes[p.GetFactor()] = p.GetExponent()
+		}
+		return nil
+	}
+	return f, primes
+}
+
+// product returns the product of the primes to their exponents.
+func product(primes map[string]uint32) *big.Int {
+	n := big.NewInt(1)
+	for p, k := range primes {
+		q, _ := new(big.Int).SetString(p, 10)
+		n.Mul(n, q.Exp(q, big.NewInt(int64(k)), nil))
+	}
+	return n
+}
+
+func TestBigFactorizer(t *testing.T) {
+	mersenne := func(p uint) *big.Int {
+		return new(big.Int).Sub(new(big.Int).Lsh(bigOne, p), bigOne)
+	}
+	tests := []map[string]uint32{
+		{"2": 10, "3": 5},
+		{"65537": 3},
+		{mersenne(61).String(): 1},
+		{mersenne(127).String(): 1},
+		// A 64-bit part left after trial division, split by pollardRho.
+		{"3": 1, "4294967291": 1, "4294967279": 1},
+		// Parts above 64 bits, split by rho.
+		{"1000003": 1, mersenne(89).String(): 1},
+		{"2": 3, "1048583": 2, "1000003": 1, mersenne(89).String(): 1},
+		// Powers of a large prime, which rho cannot split.
+		{mersenne(61).String(): 3},
+		{"1000003": 2, mersenne(89).String(): 2},
+	}
+	for _, want := range tests {
+		n := product(want)
+		f, primes := newTestFactorizer(context.Background(), new(big.Int).Set(n), time.Minute)
+		if err := f.factorize(); err != nil {
+			t.Errorf("factorizing %v failed: %v", n, err)
+			continue
+		}
+		if len(primes) != len(want) || product(primes).Cmp(n) != 0 {
+			t.Errorf("factorizing %v found %v, want %v", n, primes, want)
+		}
+		for p, k := range want {
+			if primes[p] != k {
+				t.Errorf("factorizing %v found %s^%d, want %s^%d", n, p, primes[p], p, k)
+			}
+		}
+		if len(f.work) != 0 {
+			t.Errorf("factorizing %v left %v", n, f.unfactored())
+		}
+	}
+}
+
+func TestBigFactorizerBudget(t *testing.T) {
+	// The product of two primes near 2^64 takes rho about 2^32 steps.
+	large := new(big.Int).Mul(new(big.Int).SetUint64(18446744073709551557), new(big.Int).SetUint64(18446744073709551533))
+	n := new(big.Int).Mul(large, big.NewInt(12))
+
+	start := time.Now()
+	f, primes := newTestFactorizer(context.Background(), n, 50*time.Millisecond)
+	if err := f.factorize(); err != errBudgetExhausted {
+		t.Fatalf("factorizing %v = %v, want %v", n, err, errBudgetExhausted)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("factorizing %v took %v with a budget of 50ms", n, elapsed)
+	}
+	if primes["2"] != 2 || primes["3"] != 1 || len(primes) != 2 {
+		t.Errorf("factorizing %v found %v, want 2^2 and 3", n, primes)
+	}
+	unfactored := f.unfactored()
+	if len(unfactored) != 1 || unfactored[0].GetFactor() != large.String() || unfactored[0].GetExponent() != 1 {
+		t.Errorf("factorizing %v left %v, want %v", n, unfactored, large)
+	}
+	if f.steps == 0 {
+		t.Errorf("factorizing %v counted no steps", n)
+	}
+}
+
+func TestBigFactorizerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n := new(big.Int).Mul(new(big.Int).SetUint64(18446744073709551557), new(big.Int).SetUint64(18446744073709551533))
+	f, _ := newTestFactorizer(ctx, n, time.Minute)
+	if err := f.factorize(); status.Code(err) != codes.Canceled {
+		t.Errorf("factorizing %v with a canceled context = %v, want %v", n, err, codes.Canceled)
+	}
+}
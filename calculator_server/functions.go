@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// maxCallDepth bounds how deeply user-defined functions may call each
+	// other.
+	maxCallDepth = 50
+	// maxEvaluationSteps bounds the nodes one evaluation may visit. Functions
+	// that call others several times can take exponential time, as in
+	// f1(x) = x + x, f2(x) = f1(f1(x)) and so on.
+	maxEvaluationSteps = 1000000
+)
+
+// userFunction is a function defined with DefineFunction. It is not modified
+// once defined.
+type userFunction struct {
+	*definition
+	// calls are the user-defined functions the body calls.
+	calls map[string]bool
+}
+
+func (f *userFunction) proto() *calculatorpb.Function {
+	return &calculatorpb.Function{
+		Name:       f.name,
+		Parameters: f.params,
+		Body:       f.bodySrc,
+		Definition: f.src,
+	}
+}
+
+// functionStore keeps the user-defined functions of each tenant. The map of
+// a tenant is replaced rather than modified, so evaluations can use it
+// without holding the lock.
+type functionStore struct {
+	cfg functionsConfig
+
+	mu      sync.RWMutex
+	tenants map[string]map[string]*userFunction
+}
+
+// newFunctionStore loads the functions of cfg.File, if it exists.
+func newFunctionStore(cfg functionsConfig) (*functionStore, error) {
+	st := &functionStore{cfg: cfg, tenants: map[string]map[string]*userFunction{}}
+	if cfg.File == "" {
+		return st, nil
+	}
+	data, err := os.ReadFile(cfg.File)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved map[string][]string
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", cfg.File, err)
+	}
+
+	for tenant, sources := range saved {
+		var pending []*definition
+		for _, src := range sources {
+			d, err := parseDefinition(src)
+			if err != nil {
+				return nil, fmt.Errorf("%s: tenant %q: %q: %v", cfg.File, tenant, src, err)
+			}
+			pending = append(pending, d)
+		}
+		// A function can only be checked once those it calls are known.
+		functions := map[string]*userFunction{}
+		for len(pending) > 0 {
+			var next []*definition
+			var lastErr error
+			for _, d := range pending {
+				f, err := compileFunction(d, functions)
+				if err != nil {
+					next, lastErr = append(next, d), fmt.Errorf("%q: %v", d.src, err)
+					continue
+				}
+				functions[f.name] = f
+			}
+			if len(next) == len(pending) {
+				return nil, fmt.Errorf("%s: tenant %q: %v", cfg.File, tenant, lastErr)
+			}
+			pending = next
+		}
+		st.tenants[tenant] = functions
+	}
+	return st, nil
+}
+
+// tenantFromContext returns the tenant whose functions the caller of ctx
+// uses: the tenant claim of its token, else its principal name. Anonymous
+// callers share the empty tenant.
+func tenantFromContext(ctx context.Context) string {
+	p, ok := principalFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if tenant, ok := p.Claims["tenant"].(string); ok && tenant != "" {
+		return tenant
+	}
+	return p.Name
+}
+
+// functions returns the functions of the caller's tenant. It must not be
+// modified.
+func (st *functionStore) functions(ctx context.Context) map[string]*userFunction {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.tenants[tenantFromContext(ctx)]
+}
+
+func (st *functionStore) define(ctx context.Context, src string, replace bool) (*userFunction, bool, error) {
+	d, err := parseDefinition(src)
+	if err != nil {
+		return nil, false, definitionError(src, err)
+	}
+	tenant := tenantFromContext(ctx)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	functions := maps.Clone(st.tenants[tenant])
+	if functions == nil {
+		functions = map[string]*userFunction{}
+	}
+	_, exists := functions[d.name]
+	switch {
+	case exists && !replace:
+		return nil, false, requestError{
+			code:        codes.AlreadyExists,
+			reason:      "FUNCTION_EXISTS",
+			field:       "definition",
+			description: fmt.Sprintf("%s is already defined; set replace to change it", d.name),
+			message:     fmt.Sprintf("Function %s is already defined", d.name),
+		}.err()
+	case !exists && len(functions) >= st.cfg.MaxPerTenant:
+		return nil, false, requestError{
+			code:     codes.ResourceExhausted,
+			reason:   "TOO_MANY_FUNCTIONS",
+			message:  fmt.Sprintf("At most %d functions may be defined", st.cfg.MaxPerTenant),
+			metadata: map[string]string{"max_functions": strconv.Itoa(st.cfg.MaxPerTenant)},
+		}.err()
+	}
+
+	f, err := compileFunction(d, functions)
+	if err != nil {
+		return nil, false, definitionError(src, err)
+	}
+	functions[f.name] = f
+	if exists {
+		if err := checkReplacement(f, functions); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := st.save(tenant, functions); err != nil {
+		return nil, false, err
+	}
+	st.tenants[tenant] = functions
+	return f, exists, nil
+}
+
+// checkReplacement checks that the functions still fit together after f
+// replaced a function of the same name.
+func checkReplacement(f *userFunction, functions map[string]*userFunction) error {
+	if path := callPath(f.name, f.name, functions, map[string]bool{}); path != nil {
+		return definitionError(f.src, errorAt(f.pos, "RECURSIVE_FUNCTION",
+			"%s would call itself through %s", f.name, strings.Join(path, ", ")))
+	}
+	for _, g := range functions {
+		if !g.calls[f.name] {
+			continue
+		}
+		if _, err := compileFunction(g.definition, functions); err != nil {
+			return requestError{
+				code:        codes.FailedPrecondition,
+				reason:      "FUNCTION_IN_USE",
+				field:       "definition",
+				description: fmt.Sprintf("%s calls %s and would no longer be valid: %v", g.name, f.name, err),
+				message:     fmt.Sprintf("Cannot change %s: %s calls it and would no longer be valid: %v", f.name, g.name, err),
+			}.err()
+		}
+	}
+	return nil
+}
+
+// callPath returns the functions through which from calls to, or nil if it
+// does not.
+func callPath(from, to string, functions map[string]*userFunction, visited map[string]bool) []string {
+	for name := range functions[from].calls {
+		if name == to {
+			return []string{from}
+		}
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+		if path := callPath(name, to, functions, visited); path != nil {
+			if from == to {
+				return path
+			}
+			return append([]string{from}, path...)
+		}
+	}
+	return nil
+}
+
+func (st *functionStore) list(ctx context.Context) []*userFunction {
+	functions := st.functions(ctx)
+	list := make([]*userFunction, 0, len(functions))
+	for _, f := range functions {
+		list = append(list, f)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list
+}
+
+func (st *functionStore) delete(ctx context.Context, name string) error {
+	tenant := tenantFromContext(ctx)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.tenants[tenant][name]; !ok {
+		return requestError{
+			code:        codes.NotFound,
+			reason:      "FUNCTION_NOT_FOUND",
+			field:       "name",
+			description: "is not defined",
+			message:     fmt.Sprintf("Function %q is not defined", name),
+		}.err()
+	}
+	functions := maps.Clone(st.tenants[tenant])
+	delete(functions, name)
+	for _, g := range functions {
+		if g.calls[name] {
+			return requestError{
+				code:        codes.FailedPrecondition,
+				reason:      "FUNCTION_IN_USE",
+				field:       "name",
+				description: fmt.Sprintf("is called by %s", g.name),
+				message:     fmt.Sprintf("Cannot delete %s: %s calls it", name, g.name),
+			}.err()
+		}
+	}
+
+	if err := st.save(tenant, functions); err != nil {
+		return err
+	}
+	st.tenants[tenant] = functions
+	return nil
+}
+
+// save writes the functions of every tenant to cfg.File, with those of
+// tenant replaced. The file is replaced at once, so a crash never leaves it
+// half written. st.mu must be held.
+func (st *functionStore) save(tenant string, functions map[string]*userFunction) error {
+	if st.cfg.File == "" {
+		return nil
+	}
+	tenants := maps.Clone(st.tenants)
+	tenants[tenant] = functions
+	saved := map[string][]string{}
+	for t, fns := range tenants {
+		for _, f := range fns {
+			saved[t] = append(saved[t], f.src)
+		}
+		sort.Strings(saved[t])
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := st.cfg.File + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err == nil {
+		err = os.Rename(tmp, st.cfg.File)
+	}
+	if err != nil {
+		return requestError{
+			code:    codes.Internal,
+			reason:  "FUNCTIONS_NOT_SAVED",
+			message: "The functions could not be saved",
+		}.err()
+	}
+	return nil
+}
+
+// compileFunction checks the definition d against the constants, the
+// built-in functions and functions, so that calls to it can only fail on
+// values, such as a division by zero.
+func compileFunction(d *definition, functions map[string]*userFunction) (*userFunction, error) {
+	if isReserved(d.name) {
+		return nil, errorAt(d.pos, "RESERVED_NAME", "%s is built in and cannot be defined", d.name)
+	}
+	params := map[string]bool{}
+	for i, param := range d.params {
+		if isReserved(param) {
+			return nil, errorAt(d.paramPos[i], "RESERVED_NAME", "%s is built in and cannot be a parameter", param)
+		}
+		if params[param] {
+			return nil, errorAt(d.paramPos[i], "DUPLICATE_PARAMETER", "parameter %s is repeated", param)
+		}
+		params[param] = true
+	}
+
+	f := &userFunction{definition: d, calls: map[string]bool{}}
+	var check func(n node) error
+	check = func(n node) error {
+		switch n := n.(type) {
+		case *identNode:
+			if params[n.name] {
+				return nil
+			}
+			if _, ok := constants[n.name]; ok {
+				return nil
+			}
+			if _, ok := builtins[n.name]; ok {
+				return errorAt(n.pos, "MISSING_ARGUMENTS", "%s is a function and needs arguments in parentheses", n.name)
+			}
+			return errorAt(n.pos, "UNKNOWN_NAME", "unknown name %q; a function may only use its parameters and constants", n.name)
+		case *unaryNode:
+			return check(n.operand)
+		case *binaryNode:
+			if err := check(n.left); err != nil {
+				return err
+			}
+			return check(n.right)
+		case *callNode:
+			if b, ok := builtins[n.name]; ok {
+				if len(n.args) < b.minArgs || b.maxArgs >= 0 && len(n.args) > b.maxArgs {
+					return errorAt(n.pos, "WRONG_ARGUMENT_COUNT", "%s takes %s, got %d", n.name, arity(b), len(n.args))
+				}
+			} else if g, ok := functions[n.name]; ok && n.name != d.name {
+				if len(n.args) != len(g.params) {
+					return errorAt(n.pos, "WRONG_ARGUMENT_COUNT", "%s takes %s, got %d", n.name, arguments(len(g.params)), len(n.args))
+				}
+				f.calls[n.name] = true
+			} else if n.name == d.name {
+				return errorAt(n.pos, "RECURSIVE_FUNCTION", "%s cannot call itself", n.name)
+			} else {
+				return errorAt(n.pos, "UNKNOWN_FUNCTION", "unknown function %q", n.name)
+			}
+			for _, arg := range n.args {
+				if err := check(arg); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := check(d.body); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func isReserved(name string) bool {
+	_, isConstant := constants[name]
+	_, isBuiltin := builtins[name]
+	return isConstant || isBuiltin || name == "ans"
+}
+
+func (s *server) DefineFunction(ctx context.Context, req *calculatorpb.DefineFunctionRequest) (*calculatorpb.DefineFunctionResponse, error) {
+	f, replaced, err := s.functions.define(ctx, req.GetDefinition(), req.GetReplace())
+	if err != nil {
+		return nil, err
+	}
+	loggerFromContext(ctx).Info("Function defined", "tenant", tenantFromContext(ctx), "definition", f.src, "replaced", replaced)
+	return &calculatorpb.DefineFunctionResponse{Function: f.proto(), Replaced: replaced}, nil
+}
+
+func (s *server) ListFunctions(ctx context.Context, req *calculatorpb.ListFunctionsRequest) (*calculatorpb.ListFunctionsResponse, error) {
+	res := &calculatorpb.ListFunctionsResponse{}
+	for _, f := range s.functions.list(ctx) {
+		res.Functions = append(res.Functions, f.proto())
+	}
+	return res, nil
+}
+
+func (s *server) DeleteFunction(ctx context.Context, req *calculatorpb.DeleteFunctionRequest) (*calculatorpb.DeleteFunctionResponse, error) {
+	if err := s.functions.delete(ctx, req.GetName()); err != nil {
+		return nil, err
+	}
+	loggerFromContext(ctx).Info("Function deleted", "tenant", tenantFromContext(ctx), "name", req.GetName())
+	return &calculatorpb.DeleteFunctionResponse{}, nil
+}
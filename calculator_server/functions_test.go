@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+func TestDefineFunctionErrors(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	for _, def := range []string{"g(x) = x + 1", "h(x, y) = g(x) * y"} {
+		if _, err := s.DefineFunction(ctx, &calculatorpb.DefineFunctionRequest{Definition: def}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		definition string
+		replace    bool
+		code       codes.Code
+		reason     string
+		// position counts characters from 1, and is empty for errors
+		// about the function as a whole.
+		position string
+	}{
+		{"f(x) = x + y", false, codes.InvalidArgument, "UNKNOWN_NAME", "12"},
+		{"f(x) = k(x)", false, codes.InvalidArgument, "UNKNOWN_FUNCTION", "8"},
+		{"f(x) = sqrt + x", false, codes.InvalidArgument, "MISSING_ARGUMENTS", "8"},
+		{"f(x) = 1 + f(x - 1)", false, codes.InvalidArgument, "RECURSIVE_FUNCTION", "12"},
+		{"f(x) = g(x, 1)", false, codes.InvalidArgument, "WRONG_ARGUMENT_COUNT", "8"},
+		{"f(x) = h(x)", false, codes.InvalidArgument, "WRONG_ARGUMENT_COUNT", "8"},
+		{"f(x) = atan2(x)", false, codes.InvalidArgument, "WRONG_ARGUMENT_COUNT", "8"},
+		{"sin(x) = x", false, codes.InvalidArgument, "RESERVED_NAME", "1"},
+		{"ans(x) = x", false, codes.InvalidArgument, "RESERVED_NAME", "1"},
+		{"f(pi) = pi", false, codes.InvalidArgument, "RESERVED_NAME", "3"},
+		{"f(x, x) = x", false, codes.InvalidArgument, "DUPLICATE_PARAMETER", "6"},
+		{"g(x) = 2 * x", false, codes.AlreadyExists, "FUNCTION_EXISTS", ""},
+		// h calls g, so g calling h would be a cycle.
+		{"g(x) = h(x, 2)", true, codes.InvalidArgument, "RECURSIVE_FUNCTION", "1"},
+		// h calls g with one argument.
+		{"g(x, y) = x + y", true, codes.FailedPrecondition, "FUNCTION_IN_USE", ""},
+	}
+	for _, tt := range tests {
+		_, err := s.DefineFunction(ctx, &calculatorpb.DefineFunctionRequest{Definition: tt.definition, Replace: tt.replace})
+		code, reason, metadata := errorInfo(err)
+		if code != tt.code || reason != tt.reason || metadata["position"] != tt.position {
+			t.Errorf("DefineFunction(%q) fails with %v %s at %q (%v), want %v %s at %q",
+				tt.definition, code, reason, metadata["position"], err, tt.code, tt.reason, tt.position)
+		}
+	}
+
+	// The failed definitions changed nothing.
+	res, err := s.ListFunctions(ctx, &calculatorpb.ListFunctionsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range res.GetFunctions() {
+		got = append(got, f.GetDefinition())
+	}
+	if len(got) != 2 || got[0] != "g(x) = x + 1" || got[1] != "h(x, y) = g(x) * y" {
+		t.Errorf("ListFunctions = %q, want the two functions defined first", got)
+	}
+
+	_, err = s.DeleteFunction(ctx, &calculatorpb.DeleteFunctionRequest{Name: "g"})
+	if code, reason, _ := errorInfo(err); code != codes.FailedPrecondition || reason != "FUNCTION_IN_USE" {
+		t.Errorf("deleting g, which h calls, = %v, want %v FUNCTION_IN_USE", err, codes.FailedPrecondition)
+	}
+
+	s.functions.cfg.MaxPerTenant = 2
+	_, err = s.DefineFunction(ctx, &calculatorpb.DefineFunctionRequest{Definition: "f(x) = x"})
+	if code, reason, _ := errorInfo(err); code != codes.ResourceExhausted || reason != "TOO_MANY_FUNCTIONS" {
+		t.Errorf("defining a function over the limit = %v, want %v TOO_MANY_FUNCTIONS", err, codes.ResourceExhausted)
+	}
+	// Replacing one does not count against the limit.
+	if _, err := s.DefineFunction(ctx, &calculatorpb.DefineFunctionRequest{Definition: "g(x) = x + 2", Replace: true}); err != nil {
+		t.Errorf("replacing a function at the limit: %v", err)
+	}
+}
+
+func TestFunctionTenants(t *testing.T) {
+	s := newTestServer(t)
+	as := func(name, tenant string) context.Context {
+		p := principal{Name: name, Method: "jwt"}
+		if tenant != "" {
+			p.Claims = map[string]interface{}{"tenant": tenant}
+		}
+		return context.WithValue(context.Background(), principalKey{}, p)
+	}
+	alice, bob, carol := as("alice", "acme"), as("bob", "acme"), as("carol", "")
+	anonymous := context.Background()
+
+	define := func(ctx context.Context, def string) {
+		t.Helper()
+		if _, err := s.DefineFunction(ctx, &calculatorpb.DefineFunctionRequest{Definition: def}); err != nil {
+			t.Fatalf("DefineFunction(%q): %v", def, err)
+		}
+	}
+	define(alice, "sq(x) = x ^ 2")
+	// carol is a tenant of her own, so the name is free.
+	define(carol, "sq(x) = x * 3")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want float64
+		// reason is the error reason, or empty if sq is defined.
+		reason string
+	}{
+		{"alice", alice, 16, ""},
+		{"bob, of the same tenant", bob, 16, ""},
+		{"carol", carol, 12, ""},
+		{"anonymous", anonymous, 0, "UNKNOWN_FUNCTION"},
+	}
+	for _, tt := range tests {
+		res, err := s.Evaluate(tt.ctx, &calculatorpb.EvaluateRequest{Expression: "sq(4)"})
+		_, reason, _ := errorInfo(err)
+		if reason != tt.reason || tt.reason == "" && res.GetResult() != tt.want {
+			t.Errorf("Evaluate(sq(4)) as %s = %v, %v, want %v %s", tt.name, res.GetResult(), err, tt.want, tt.reason)
+		}
+
+		list, err := s.ListFunctions(tt.ctx, &calculatorpb.ListFunctionsRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(list.GetFunctions()); n != 1 && tt.reason == "" || n != 0 && tt.reason != "" {
+			t.Errorf("ListFunctions as %s = %v", tt.name, list.GetFunctions())
+		}
+	}
+
+	// Callers of other tenants cannot delete the function.
+	for _, ctx := range []context.Context{anonymous, as("mallory", "other")} {
+		_, err := s.DeleteFunction(ctx, &calculatorpb.DeleteFunctionRequest{Name: "sq"})
+		if code, reason, _ := errorInfo(err); code != codes.NotFound || reason != "FUNCTION_NOT_FOUND" {
+			t.Errorf("deleting another tenant's function = %v, want %v FUNCTION_NOT_FOUND", err, codes.NotFound)
+		}
+	}
+	if _, err := s.DeleteFunction(bob, &calculatorpb.DeleteFunctionRequest{Name: "sq"}); err != nil {
+		t.Fatalf("deleting a function of the same tenant: %v", err)
+	}
+	if res, err := s.Evaluate(carol, &calculatorpb.EvaluateRequest{Expression: "sq(4)"}); err != nil || res.GetResult() != 12 {
+		t.Errorf("Evaluate(sq(4)) as carol after acme deleted sq = %v, %v, want 12", res.GetResult(), err)
+	}
+}
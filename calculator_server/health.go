@@ -0,0 +1,56 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// calculatorServiceName is the name CalculatorService reports its health under.
+const calculatorServiceName = "calculator.CalculatorService"
+
+// healthCheck is a dependency the server needs to be able to serve.
+type healthCheck struct {
+	name  string
+	check func() error
+}
+
+// setServing sets the status of the server as a whole ("") and of
+// CalculatorService.
+func setServing(hs *health.Server, status healthpb.HealthCheckResponse_ServingStatus) {
+	hs.SetServingStatus("", status)
+	hs.SetServingStatus(calculatorServiceName, status)
+}
+
+// monitorHealth runs checks every interval and reports NOT_SERVING while any
+// of them fails. It stops having an effect once hs.Shutdown is called.
+func monitorHealth(hs *health.Server, interval time.Duration, checks []healthCheck) {
+	if len(checks) == 0 {
+		return
+	}
+
+	go func() {
+		healthy := true
+		for range time.Tick(interval) {
+			var failed []string
+			for _, c := range checks {
+				if err := c.check(); err != nil {
+					slog.Warn("Health check failed", "check", c.name, "error", err)
+					failed = append(failed, c.name)
+				}
+			}
+
+			switch {
+			case healthy && len(failed) > 0:
+				slog.Warn("Server is not serving", "failed_checks", failed)
+				setServing(hs, healthpb.HealthCheckResponse_NOT_SERVING)
+			case !healthy && len(failed) == 0:
+				slog.Info("Server is serving again")
+				setServing(hs, healthpb.HealthCheckResponse_SERVING)
+			}
+			healthy = len(failed) == 0
+		}
+	}()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// clientIdentity is who a caller proved to be with its client certificate.
+type clientIdentity struct {
+	CommonName string
+	DNSNames   []string
+	URIs       []string
+	Emails     []string
+}
+
+func (id clientIdentity) String() string {
+	if id.CommonName != "" {
+		return id.CommonName
+	}
+	var sans []string
+	sans = append(sans, id.DNSNames...)
+	sans = append(sans, id.URIs...)
+	sans = append(sans, id.Emails...)
+	return strings.Join(sans, ",")
+}
+
+// clientIdentityFromContext returns the identity of the caller of an RPC, or
+// false if the connection was not authenticated with a verified client
+// certificate (i.e. mutual TLS is off).
+func clientIdentityFromContext(ctx context.Context) (clientIdentity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return clientIdentity{}, false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return clientIdentity{}, false
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	id := clientIdentity{
+		CommonName: cert.Subject.CommonName,
+		DNSNames:   cert.DNSNames,
+		Emails:     cert.EmailAddresses,
+	}
+	for _, uri := range cert.URIs {
+		id.URIs = append(id.URIs, uri.String())
+	}
+	return id, true
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDHeader carries the request ID. Clients may set it to correlate
+// their logs with ours; otherwise the server generates one. Either way it is
+// sent back in the response header.
+const requestIDHeader = "x-request-id"
+
+type requestIDKey struct{}
+
+// newLogger returns the logger for the configured level and format.
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := parseLogLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("log_format %q: must be json or text", format)
+}
+
+// loggerFromContext returns the default logger, tagged with the request ID and
+// trace ID of the RPC ctx belongs to.
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		logger = logger.With("request_id", id)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logger = logger.With("trace_id", sc.TraceID().String())
+	}
+	return logger
+}
+
+// loggedCall collects what the interceptors inside the logging one learn
+// about a call, for its access log line.
+type loggedCall struct {
+	principal *principal
+}
+
+type loggedCallKey struct{}
+
+// logPrincipal records the caller authenticated for the RPC of ctx, so that
+// the access log names it.
+func logPrincipal(ctx context.Context, p principal) {
+	if c, ok := ctx.Value(loggedCallKey{}).(*loggedCall); ok {
+		c.principal = &p
+	}
+}
+
+// withRequestID stores the caller's request ID, or a new one, in ctx.
+func withRequestID(ctx context.Context) (context.Context, string) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDHeader); len(ids) > 0 && ids[0] != "" {
+			return context.WithValue(ctx, requestIDKey{}, ids[0]), ids[0]
+		}
+	}
+	b := make([]byte, 8)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+	return context.WithValue(ctx, requestIDKey{}, id), id
+}
+
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, id := withRequestID(ctx)
+	ctx = context.WithValue(ctx, loggedCallKey{}, &loggedCall{})
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, id))
+
+	logger := loggerFromContext(ctx)
+	logger.Debug("Request received", "method", info.FullMethod, "request", req)
+
+	start := time.Now()
+	res, err := handler(ctx, req)
+	logCall(ctx, logger, info.FullMethod, start, err)
+	return res, err
+}
+
+func loggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, id := withRequestID(ss.Context())
+	ctx = context.WithValue(ctx, loggedCallKey{}, &loggedCall{})
+	ss.SetHeader(metadata.Pairs(requestIDHeader, id))
+
+	logger := loggerFromContext(ctx)
+	logger.Debug("Stream opened", "method", info.FullMethod)
+
+	stream := &countingStream{ServerStream: ss, ctx: ctx}
+	start := time.Now()
+	err := handler(srv, stream)
+	logCall(ctx, logger, info.FullMethod, start, err, "received", stream.received, "sent", stream.sent)
+	return err
+}
+
+// logCall writes the access log line for a finished RPC.
+func logCall(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error, attrs ...interface{}) {
+	code := status.Code(err)
+	attrs = append([]interface{}{
+		"method", method,
+		"code", code.String(),
+		"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+	}, attrs...)
+	if p, ok := peer.FromContext(ctx); ok {
+		attrs = append(attrs, "peer", p.Addr.String())
+	}
+	if id, ok := clientIdentityFromContext(ctx); ok {
+		attrs = append(attrs, "client", id.String(), "client_dns_names", id.DNSNames)
+		if len(id.URIs) > 0 {
+			attrs = append(attrs, "client_uris", id.URIs)
+		}
+	}
+	if c, ok := ctx.Value(loggedCallKey{}).(*loggedCall); ok && c.principal != nil {
+		attrs = append(attrs, "principal", c.principal.Name, "auth_method", c.principal.Method)
+	}
+	if err != nil {
+		attrs = append(attrs, "error", status.Convert(err).Message())
+	}
+	logger.Log(ctx, codeLevel(code), "Call finished", attrs...)
+}
+
+// codeLevel is the log level for a call that ended with code: errors caused by
+// the client are informational, those the server should look into are not.
+func codeLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return slog.LevelInfo
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
+
+// countingStream counts the messages of a stream and carries the context
+// with the request ID to the handler.
+type countingStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	received int
+	sent     int
+}
+
+func (s *countingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
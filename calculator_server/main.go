@@ -2,100 +2,275 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 	"io"
 	"log"
+	"log/slog"
 	"math"
 	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 )
 
-type server struct{}
+type server struct {
+	big       bigNumbersConfig
+	stats     statisticsConfig
+	sessions  *sessionStore
+	functions *functionStore
+}
 
 func main() {
-	fmt.Println("Server is running...")
+	cfg, err := loadConfig(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	logger, _ := newLogger(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	slog.SetDefault(logger)
+
+	slog.Info("Server is running", "address", cfg.Address, "tls", cfg.TLS.Enabled, "auth", cfg.Auth.Enabled)
+
+	// Tracing
+	shutdownTracing, err := setupTracing(cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
 
 	// Make a listener
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	lis, err := net.Listen("tcp", cfg.Address)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
+	// Server options
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(cfg.Limits.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.Limits.MaxSendMsgSize),
+		grpc.ConnectionTimeout(cfg.Limits.ConnectionTimeout),
+		grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+		)),
+	}
+	if cfg.Limits.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(cfg.Limits.MaxConcurrentStreams)))
+	}
+
 	// SSL config
-	tls := false
-	opts := []grpc.ServerOption{}
-	if tls {
-		certFile := "../ssl/server.crt"
-		keyFile := "../ssl/server.pem"
-		creds, sslErr := credentials.NewServerTLSFromFile(certFile, keyFile)
+	var healthChecks []healthCheck
+	if cfg.TLS.Enabled {
+		tlsConfig, reloader, sslErr := serverTLSConfig(cfg.TLS)
 		if sslErr != nil {
 			log.Fatalf("Faild loading certificates: %v", sslErr)
 		}
-		opts = append(opts, grpc.Creds(creds))
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		healthChecks = append(healthChecks, healthCheck{"certificate", reloader.checkValidity})
+	}
+
+	// Interceptors, outermost first
+	chain, err := newInterceptors(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up interceptors: %v", err)
+	}
+	metrics, tracker := chain.metrics, chain.tracker
+
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(chain.unary...),
+		grpc.ChainStreamInterceptor(chain.stream...),
+	)
+
+	functions, err := newFunctionStore(cfg.Functions)
+	if err != nil {
+		log.Fatalf("Failed to load functions: %v", err)
 	}
 
 	// Make a gRPC server
 	grpcServer := grpc.NewServer(opts...)
-	calculatorpb.RegisterCalculatorServiceServer(grpcServer, &server{})
+	calculatorpb.RegisterCalculatorServiceServer(grpcServer, &server{
+		big:       cfg.BigNumbers,
+		stats:     cfg.Statistics,
+		sessions:  newSessionStore(cfg.Sessions),
+		functions: functions,
+	})
+
+	// Register health service, not serving until the server runs.
+	healthServer := health.NewServer()
+	setServing(healthServer, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
 
 	// Register reflection service on gRPC server.
-	reflection.Register(grpcServer)
+	if cfg.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	// Serve metrics
+	if metrics != nil {
+		metrics.initialize(grpcServer)
+		metricsServer, err := metrics.serve(cfg.MetricsAddress)
+		if err != nil {
+			log.Fatalf("Failed to serve metrics: %v", err)
+		}
+		defer metricsServer.Close()
+	}
 
 	// Run the gRPC server
-	if err := grpcServer.Serve(lis); err != nil {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(lis)
+	}()
+	setServing(healthServer, healthpb.HealthCheckResponse_SERVING)
+	monitorHealth(healthServer, cfg.HealthCheckInterval, healthChecks)
+
+	// Shut down gracefully on SIGINT/SIGTERM
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
 		log.Fatalf("Failed to serve: %v", err)
+	case sig := <-signals:
+		slog.Info("Received signal", "signal", sig.String())
+		healthServer.Shutdown()
+		gracefulShutdown(grpcServer, tracker, cfg.ShutdownDrainDelay, cfg.ShutdownGracePeriod, signals)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
 	}
 }
 
-func (*server) Sum(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
-	fmt.Printf("Received Sum RPC: %v\n", req)
+// interceptors are the interceptor chains of the server, outermost first.
+type interceptors struct {
+	unary  []grpc.UnaryServerInterceptor
+	stream []grpc.StreamServerInterceptor
+	// metrics is nil unless cfg.MetricsAddress is set.
+	metrics *serverMetrics
+	tracker *callTracker
+}
+
+// newInterceptors builds the interceptor chains cfg asks for: logging,
+// metrics, panic recovery, authentication, authorization, rate limiting and
+// call tracking.
+func newInterceptors(cfg config) (interceptors, error) {
+	unaryInterceptors := []grpc.UnaryServerInterceptor{loggingUnaryInterceptor}
+	streamInterceptors := []grpc.StreamServerInterceptor{loggingStreamInterceptor}
+
+	var metrics *serverMetrics
+	if cfg.MetricsAddress != "" {
+		metrics = newServerMetrics()
+		unaryInterceptors = append(unaryInterceptors, metrics.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, metrics.streamInterceptor)
+	}
+
+	unaryInterceptors = append(unaryInterceptors, recoverUnaryInterceptor)
+	streamInterceptors = append(streamInterceptors, recoverStreamInterceptor)
 
+	if cfg.Auth.Enabled {
+		auth, err := newAuthenticator(cfg.Auth)
+		if err != nil {
+			return interceptors{}, fmt.Errorf("loading credentials: %w", err)
+		}
+		unaryInterceptors = append(unaryInterceptors, auth.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, auth.streamInterceptor)
+	}
+
+	if cfg.Auth.PolicyFile != "" {
+		authz, err := newAuthorizer(cfg.Auth.PolicyFile)
+		if err != nil {
+			return interceptors{}, fmt.Errorf("loading policy: %w", err)
+		}
+		if err := watchFiles([]string{cfg.Auth.PolicyFile}, authz.reloadAndLog); err != nil {
+			return interceptors{}, fmt.Errorf("watching policy file: %w", err)
+		}
+		reloadOnSignal(authz.reloadAndLog, syscall.SIGHUP)
+		unaryInterceptors = append(unaryInterceptors, authz.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, authz.streamInterceptor)
+	}
+
+	if len(cfg.RateLimits) > 0 {
+		limiter := newRateLimiter(cfg.RateLimits)
+		unaryInterceptors = append(unaryInterceptors, limiter.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, limiter.streamInterceptor)
+	}
+
+	tracker := newCallTracker()
+	unaryInterceptors = append(unaryInterceptors, tracker.unaryInterceptor)
+	streamInterceptors = append(streamInterceptors, tracker.streamInterceptor)
+
+	return interceptors{
+		unary:   unaryInterceptors,
+		stream:  streamInterceptors,
+		metrics: metrics,
+		tracker: tracker,
+	}, nil
+}
+
+func (*server) Sum(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
 	firstNumber := req.GetFirstNumber()
 	secondNumber := req.GetSecondUmber()
 
-	sum := firstNumber + secondNumber
+	sum, err := addInt32(firstNumber, secondNumber, req.GetOverflowMode())
+	if err != nil {
+		return nil, err
+	}
 
 	res := &calculatorpb.SumResponse{
-		SumResult: sum,
+		SumResult:     sum.sum,
+		WideSumResult: sum.wide,
+		Overflowed:    sum.overflowed,
 	}
 
 	return res, nil
 }
 
 func (*server) PrimeNumberDecomposition(req *calculatorpb.PrimeNumberDecompositionRequest, stream calculatorpb.CalculatorService_PrimeNumberDecompositionServer) error {
-	fmt.Printf("Received PrimeNumberDecomposition RPC: %v\n", req)
-
 	number := req.Number
-	divisor := int64(2)
-
-	for number > 1 {
-		if number%divisor == 0 {
-			err := stream.Send(&calculatorpb.PrimeNumberDecompositionResponse{
-				PrimeFactor: divisor,
-			})
-			if err != nil {
-				log.Fatalf("Failed to send response: %v\n", err)
-			}
 
-			number = number / divisor
-		} else {
-			divisor++
-			fmt.Printf("Divisor has increased to %v", divisor)
-		}
+	if number < 1 {
+		return requestError{
+			code:        codes.InvalidArgument,
+			reason:      "NUMBER_NOT_POSITIVE",
+			field:       "number",
+			description: "must be positive",
+			message:     fmt.Sprintf("Cannot decompose %v: only positive numbers have prime factors", number),
+			metadata:    map[string]string{"number": strconv.FormatInt(number, 10)},
+		}.err()
 	}
 
-	return nil
+	ctx := stream.Context()
+	return factorize(ctx, uint64(number), func(factor uint64) error {
+		trace.SpanFromContext(ctx).AddEvent("prime factor",
+			trace.WithAttributes(attribute.Int64("factor", int64(factor))))
+		err := stream.Send(&calculatorpb.PrimeNumberDecompositionResponse{
+			PrimeFactor: int64(factor),
+		})
+		if err != nil {
+			return streamError(ctx, err, "sending prime factor")
+		}
+		return nil
+	})
 }
 
 func (*server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAverageServer) error {
-	fmt.Printf("Received ComputeAverage RPC\n")
-
 	sum := float64(0)
 	count := float64(0)
 
@@ -103,12 +278,21 @@ func (*server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAvera
 		req, err := stream.Recv()
 
 		if err == io.EOF {
+			if count == 0 {
+				return requestError{
+					code:        codes.InvalidArgument,
+					reason:      "EMPTY_STREAM",
+					field:       "number",
+					description: "at least one number is required",
+					message:     "Cannot average an empty stream of numbers",
+				}.err()
+			}
 			return stream.SendAndClose(&calculatorpb.ComputeAverageResponse{
 				Average: sum / count,
 			})
 		}
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
+			return streamError(stream.Context(), err, "reading client stream")
 		}
 
 		sum += float64(req.GetNumber())
@@ -117,9 +301,8 @@ func (*server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAvera
 }
 
 func (*server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServer) error {
-	fmt.Printf("Received FindMaximum RPC\n")
-
-	maximum := int32(0)
+	var maximum int32
+	seen := false
 
 	for {
 		req, err := stream.Recv()
@@ -127,33 +310,38 @@ func (*server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServ
 			return nil
 		}
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
-			return err
+			return streamError(stream.Context(), err, "reading client stream")
 		}
 
-		if req.Number > maximum {
+		span := trace.SpanFromContext(stream.Context())
+		span.AddEvent("number received", trace.WithAttributes(attribute.Int("number", int(req.Number))))
+
+		if !seen || req.Number > maximum {
 			maximum = req.Number
+			seen = true
+			span.AddEvent("maximum changed", trace.WithAttributes(attribute.Int("maximum", int(maximum))))
 			err := stream.Send(&calculatorpb.FindMaximumResponse{
 				Maximum: maximum,
 			})
 			if err != nil {
-				log.Fatalf("Error while sending client stream: %v", err)
-				return err
+				return streamError(stream.Context(), err, "sending maximum")
 			}
 		}
 	}
 }
 
 func (*server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
-	fmt.Println("Received SquareRoot RPC")
-
 	number := req.Number
 
 	if number < 0 {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Received a negative number: %v", number),
-		)
+		return nil, requestError{
+			code:        codes.InvalidArgument,
+			reason:      "NEGATIVE_NUMBER",
+			field:       "number",
+			description: "must not be negative",
+			message:     fmt.Sprintf("Received a negative number: %v", number),
+			metadata:    map[string]string{"number": strconv.Itoa(int(number))},
+		}.err()
 	}
 
 	return &calculatorpb.SquareRootResponse{
@@ -162,13 +350,10 @@ func (*server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootReque
 }
 
 func (*server) SumWithDeadLine(ctx context.Context, req *calculatorpb.SumWithDeadLineRequest) (*calculatorpb.SumWithDeadLineResponse, error) {
-	fmt.Printf("Received SumWithDeadLine RPC: %v\n", req)
-
 	for i := 0; i < 3; i++ {
 
-		if ctx.Err() == context.Canceled {
-			fmt.Println("The client canceled the request")
-			return nil, status.Error(codes.Canceled, "The client canceled the request")
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
 		}
 
 		time.Sleep(1 * time.Second)
@@ -177,10 +362,15 @@ func (*server) SumWithDeadLine(ctx context.Context, req *calculatorpb.SumWithDea
 	firstNumber := req.GetFirstNumber()
 	secondNumber := req.GetSecondUmber()
 
-	sum := firstNumber + secondNumber
+	sum, err := addInt32(firstNumber, secondNumber, req.GetOverflowMode())
+	if err != nil {
+		return nil, err
+	}
 
 	res := &calculatorpb.SumWithDeadLineResponse{
-		SumResult: sum,
+		SumResult:     sum.sum,
+		WideSumResult: sum.wide,
+		Overflowed:    sum.overflowed,
 	}
 
 	return res, nil
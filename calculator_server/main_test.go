@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// handlerResult is what the server returned for one call.
+type handlerResult struct {
+	method string
+	code   codes.Code
+}
+
+// heldStream lets a handler send one message and then holds its later sends
+// until the call is canceled, so that a test can cancel a stream partway.
+type heldStream struct {
+	grpc.ServerStream
+	sent int
+}
+
+func (s *heldStream) SendMsg(m interface{}) error {
+	s.sent++
+	if s.sent > 1 {
+		<-s.Context().Done()
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// startServer serves the calculator over an in-memory connection with the
+// interceptors of the default configuration. The codes the server returns
+// are sent on the channel.
+func startServer(t *testing.T) (calculatorpb.CalculatorServiceClient, <-chan handlerResult) {
+	t.Helper()
+	cfg := defaultConfig()
+	cfg.MetricsAddress = ""
+	chain, err := newInterceptors(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	functions, err := newFunctionStore(cfg.Functions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := make(chan handlerResult, 10)
+	record := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		results <- handlerResult{info.FullMethod, status.Code(err)}
+		return err
+	}
+	hold := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &heldStream{ServerStream: ss})
+	}
+	streamInterceptors := append([]grpc.StreamServerInterceptor{record}, chain.stream...)
+	streamInterceptors = append(streamInterceptors, hold)
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(chain.unary...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	)
+	calculatorpb.RegisterCalculatorServiceServer(s, &server{
+		big:       cfg.BigNumbers,
+		stats:     cfg.Statistics,
+		sessions:  newSessionStore(cfg.Sessions),
+		functions: functions,
+	})
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return calculatorpb.NewCalculatorServiceClient(cc), results
+}
+
+func TestCanceledStreams(t *testing.T) {
+	tests := []struct {
+		name string
+		// start starts the call and returns once it is partway through.
+		start func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error
+	}{
+		{
+			"PrimeNumberDecomposition",
+			func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error {
+				// 2^10 has ten factors; the second send is held.
+				stream, err := c.PrimeNumberDecomposition(ctx, &calculatorpb.PrimeNumberDecompositionRequest{Number: 1024})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+		},
+		{
+			"ComputeAverage",
+			func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error {
+				stream, err := c.ComputeAverage(ctx)
+				if err != nil {
+					return err
+				}
+				for _, n := range []int32{1, 2, 3} {
+					if err := stream.Send(&calculatorpb.ComputeAverageRequest{Number: n}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			"FindMaximum",
+			func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error {
+				stream, err := c.FindMaximum(ctx)
+				if err != nil {
+					return err
+				}
+				if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: 1}); err != nil {
+					return err
+				}
+				if _, err := stream.Recv(); err != nil {
+					return err
+				}
+				// The new maximum is held.
+				return stream.Send(&calculatorpb.FindMaximumRequest{Number: 2})
+			},
+		},
+	}
+
+	client, results := startServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if err := tt.start(ctx, client); err != nil {
+				t.Fatalf("starting the call: %v", err)
+			}
+			cancel()
+
+			select {
+			case res := <-results:
+				method := "/calculator.CalculatorService/" + tt.name
+				if res.method != method || res.code != codes.Canceled {
+					t.Errorf("server returned %v for %s, want %v for %s", res.code, res.method, codes.Canceled, method)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("the handler did not return after the call was canceled")
+			}
+		})
+	}
+
+	res, err := client.Sum(context.Background(), &calculatorpb.SumRequest{FirstNumber: 3, SecondUmber: 10})
+	if err != nil {
+		t.Fatalf("Sum after canceled streams: %v", err)
+	}
+	if res.GetSumResult() != 13 {
+		t.Errorf("Sum(3, 10) = %d, want 13", res.GetSumResult())
+	}
+}
+
+// writeFile writes data to a file named name in a temporary directory and
+// returns its path.
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAuthenticate(t *testing.T) {
+	secret := "a shared secret of enough length"
+	a, err := newAuthenticator(authConfig{
+		Enabled:     true,
+		APIKeysFile: writeFile(t, "keys.yaml", "- {name: ci, key: s3cret, roles: [reader]}\n"),
+		JWT: jwtConfig{
+			Issuer:   "https://issuer.example.com",
+			Audience: "calculator",
+			Keys:     []jwtKeyConfig{{ID: "k1", Algorithm: "HS256", File: writeFile(t, "secret", secret+"\n")}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(method jwt.SigningMethod, key interface{}, change func(jwt.MapClaims)) string {
+		claims := jwt.MapClaims{
+			"sub":   "alice",
+			"iss":   "https://issuer.example.com",
+			"aud":   "calculator",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": []string{"admin"},
+		}
+		if change != nil {
+			change(claims)
+		}
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	hs256 := func(change func(jwt.MapClaims)) string {
+		return sign(jwt.SigningMethodHS256, []byte(secret), change)
+	}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		// want is the name of the caller, or empty if the call is rejected.
+		want string
+	}{
+		{"API key", metadata.Pairs(apiKeyHeader, "s3cret"), "ci"},
+		{"wrong API key", metadata.Pairs(apiKeyHeader, "guess"), ""},
+		{"empty API key", metadata.Pairs(apiKeyHeader, ""), ""},
+		{"token", metadata.Pairs(authorizationHeader, "Bearer "+hs256(nil)), "alice"},
+		{"lowercase scheme", metadata.Pairs(authorizationHeader, "bearer "+hs256(nil)), "alice"},
+		{"alg none", metadata.Pairs(authorizationHeader, "Bearer "+sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, nil)), ""},
+		{"other algorithm", metadata.Pairs(authorizationHeader, "Bearer "+sign(jwt.SigningMethodHS384, []byte(secret), nil)), ""},
+		{"wrong secret", metadata.Pairs(authorizationHeader, "Bearer "+sign(jwt.SigningMethodHS256, []byte("another secret"), nil)), ""},
+		{"expired", metadata.Pairs(authorizationHeader, "Bearer "+hs256(func(c jwt.MapClaims) {
+			c["exp"] = time.Now().Add(-time.Minute).Unix()
+		})), ""},
+		{"expired within the leeway", metadata.Pairs(authorizationHeader, "Bearer "+hs256(func(c jwt.MapClaims) {
+			c["exp"] = time.Now().Add(-jwtLeeway / 2).Unix()
+		})), "alice"},
+		{"no expiry", metadata.Pairs(authorizationHeader, "Bearer "+hs256(func(c jwt.MapClaims) { delete(c, "exp") })), ""},
+		{"wrong issuer", metadata.Pairs(authorizationHeader, "Bearer "+hs256(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" })), ""},
+		{"wrong audience", metadata.Pairs(authorizationHeader, "Bearer "+hs256(func(c jwt.MapClaims) { c["aud"] = "billing" })), ""},
+		{"no subject", metadata.Pairs(authorizationHeader, "Bearer "+hs256(func(c jwt.MapClaims) { delete(c, "sub") })), ""},
+		{"roles not a list", metadata.Pairs(authorizationHeader, "Bearer "+hs256(func(c jwt.MapClaims) { c["roles"] = "admin" })), ""},
+		{"not a bearer token", metadata.Pairs(authorizationHeader, "Basic YWxpY2U6cHc="), ""},
+		{"bearer without a token", metadata.Pairs(authorizationHeader, "Bearer"), ""},
+		{"malformed token", metadata.Pairs(authorizationHeader, "Bearer not.a.token"), ""},
+		{"no credentials", metadata.Pairs("x-other", "1"), ""},
+		{"no metadata", nil, ""},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.md != nil {
+			ctx = metadata.NewIncomingContext(ctx, tt.md)
+		}
+		var got principal
+		info := &grpc.UnaryServerInfo{FullMethod: "/calculator.CalculatorService/Sum"}
+		_, err := a.unaryInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			got, _ = principalFromContext(ctx)
+			return nil, nil
+		})
+		switch {
+		case tt.want == "" && status.Code(err) != codes.Unauthenticated:
+			t.Errorf("%s: call returned %v, want %v", tt.name, err, codes.Unauthenticated)
+		case tt.want != "" && (err != nil || got.Name != tt.want):
+			t.Errorf("%s: call returned %v as %q, want success as %q", tt.name, err, got.Name, tt.want)
+		}
+	}
+
+	// Health checks need no credentials.
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	if _, err := a.unaryInterceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	}); err != nil {
+		t.Errorf("health check without credentials returned %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Values of the grpc_type label.
+const (
+	unaryType        = "unary"
+	clientStreamType = "client_stream"
+	serverStreamType = "server_stream"
+	bidiStreamType   = "bidi_stream"
+)
+
+// serverMetrics are the Prometheus metrics of the gRPC server, labeled by
+// service, method and RPC type.
+type serverMetrics struct {
+	registry *prometheus.Registry
+
+	started     *prometheus.CounterVec
+	handled     *prometheus.CounterVec
+	handling    *prometheus.HistogramVec
+	msgReceived *prometheus.CounterVec
+	msgSent     *prometheus.CounterVec
+	inFlight    *prometheus.GaugeVec
+}
+
+func newServerMetrics() *serverMetrics {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "Total number of RPCs started on the server.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, append(labels, "grpc_code")),
+		handling: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Histogram of response latency (seconds) of RPCs handled by the server.",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, labels),
+		msgReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Total number of stream messages received from clients.",
+		}, labels),
+		msgSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of stream messages sent to clients.",
+		}, labels),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "grpc_server_in_flight_requests",
+			Help: "Number of RPCs currently being handled by the server.",
+		}, labels),
+	}
+
+	m.registry.MustRegister(
+		m.started, m.handled, m.handling, m.msgReceived, m.msgSent, m.inFlight,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// initialize creates the series of every method registered on s, so that
+// they are exported with a zero value before the first call.
+func (m *serverMetrics) initialize(s *grpc.Server) {
+	for service, info := range s.GetServiceInfo() {
+		for _, method := range info.Methods {
+			typ := rpcType(method.IsClientStream, method.IsServerStream)
+			m.started.WithLabelValues(typ, service, method.Name)
+			m.handling.WithLabelValues(typ, service, method.Name)
+			m.msgReceived.WithLabelValues(typ, service, method.Name)
+			m.msgSent.WithLabelValues(typ, service, method.Name)
+			m.inFlight.WithLabelValues(typ, service, method.Name)
+			for c := codes.OK; c <= codes.Unauthenticated; c++ {
+				m.handled.WithLabelValues(typ, service, method.Name, c.String())
+			}
+		}
+	}
+}
+
+// serve exposes the metrics in the Prometheus text format on /metrics.
+func (m *serverMetrics) serve(address string) (*http.Server, error) {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("metrics listener: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			slog.Error("Metrics server failed", "error", err)
+		}
+	}()
+	return srv, nil
+}
+
+func (m *serverMetrics) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	done := m.begin(unaryType, info.FullMethod)
+	res, err := handler(ctx, req)
+	done(err)
+	return res, err
+}
+
+func (m *serverMetrics) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	typ := rpcType(info.IsClientStream, info.IsServerStream)
+	service, method := splitMethodName(info.FullMethod)
+
+	done := m.begin(typ, info.FullMethod)
+	err := handler(srv, &metricsStream{
+		ServerStream: ss,
+		received:     m.msgReceived.WithLabelValues(typ, service, method),
+		sent:         m.msgSent.WithLabelValues(typ, service, method),
+	})
+	done(err)
+	return err
+}
+
+// begin records the start of a call and returns the function recording its end.
+func (m *serverMetrics) begin(typ, fullMethod string) func(err error) {
+	service, method := splitMethodName(fullMethod)
+	start := time.Now()
+	m.started.WithLabelValues(typ, service, method).Inc()
+	inFlight := m.inFlight.WithLabelValues(typ, service, method)
+	inFlight.Inc()
+
+	return func(err error) {
+		inFlight.Dec()
+		m.handled.WithLabelValues(typ, service, method, status.Code(err).String()).Inc()
+		m.handling.WithLabelValues(typ, service, method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// metricsStream counts the messages of a stream.
+type metricsStream struct {
+	grpc.ServerStream
+	received prometheus.Counter
+	sent     prometheus.Counter
+}
+
+func (s *metricsStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Inc()
+	}
+	return err
+}
+
+func (s *metricsStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Inc()
+	}
+	return err
+}
+
+func rpcType(clientStream, serverStream bool) string {
+	switch {
+	case clientStream && serverStream:
+		return bidiStreamType
+	case clientStream:
+		return clientStreamType
+	case serverStream:
+		return serverStreamType
+	}
+	return unaryType
+}
+
+// splitMethodName splits "/package.Service/Method" into its service and
+// method names.
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// policy decides which callers may call which methods. Rules are checked in
+// order and the first one that matches both the method and the caller
+// decides; Default applies when none does.
+type policy struct {
+	Default string       `yaml:"default"`
+	Rules   []policyRule `yaml:"rules"`
+}
+
+type policyRule struct {
+	// Methods are full method names such as
+	// /calculator.CalculatorService/Sum; * matches any part of a name.
+	Methods []string `yaml:"methods"`
+	// Principals, Roles and Claims narrow the rule down to some callers. A
+	// caller matches if it is one of Principals ("*" for anyone
+	// authenticated), has one of Roles and has every claim in Claims. A rule
+	// without any of them matches every caller.
+	Principals []string          `yaml:"principals"`
+	Roles      []string          `yaml:"roles"`
+	Claims     map[string]string `yaml:"claims"`
+	// Effect is allow or deny.
+	Effect string `yaml:"effect"`
+}
+
+func loadPolicy(file string) (*policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy: %v", err)
+	}
+	p := &policy{Default: "deny"}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, fmt.Errorf("parsing policy file %s: %v", file, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("policy file %s: %v", file, err)
+	}
+	return p, nil
+}
+
+func (p *policy) validate() error {
+	if p.Default != "allow" && p.Default != "deny" {
+		return fmt.Errorf("default %q: must be allow or deny", p.Default)
+	}
+	for i, rule := range p.Rules {
+		if rule.Effect != "allow" && rule.Effect != "deny" {
+			return fmt.Errorf("rule %d: effect %q: must be allow or deny", i+1, rule.Effect)
+		}
+		if len(rule.Methods) == 0 {
+			return fmt.Errorf("rule %d: no methods", i+1)
+		}
+		for _, pattern := range rule.Methods {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %d: method %q: %v", i+1, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// allows reports whether caller may call method. ok is false for anonymous
+// callers.
+func (p *policy) allows(method string, caller principal, ok bool) bool {
+	for _, rule := range p.Rules {
+		if rule.matchesMethod(method) && rule.matchesCaller(caller, ok) {
+			return rule.Effect == "allow"
+		}
+	}
+	return p.Default == "allow"
+}
+
+func (r *policyRule) matchesMethod(method string) bool {
+	for _, pattern := range r.Methods {
+		if matched, _ := path.Match(pattern, method); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *policyRule) matchesCaller(caller principal, ok bool) bool {
+	if len(r.Principals) == 0 && len(r.Roles) == 0 && len(r.Claims) == 0 {
+		return true
+	}
+	if !ok {
+		return false
+	}
+	if len(r.Principals) > 0 && !contains(r.Principals, "*") && !contains(r.Principals, caller.Name) {
+		return false
+	}
+	if len(r.Roles) > 0 && !containsAny(r.Roles, caller.Roles) {
+		return false
+	}
+	for name, want := range r.Claims {
+		if !claimMatches(caller.Claims[name], want) {
+			return false
+		}
+	}
+	return true
+}
+
+// claimMatches reports whether the claim value v is want or, for a list,
+// contains want.
+func claimMatches(v interface{}, want string) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case []interface{}:
+		for _, item := range v {
+			if fmt.Sprint(item) == want {
+				return true
+			}
+		}
+		return false
+	}
+	return fmt.Sprint(v) == want
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		if contains(list, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizer enforces the policy in a file, reloading it when it changes.
+type authorizer struct {
+	file string
+
+	mu     sync.RWMutex
+	policy *policy
+}
+
+func newAuthorizer(file string) (*authorizer, error) {
+	a := &authorizer{file: file}
+	if err := a.reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// reload loads the policy file again. On failure the previous policy stays
+// in force.
+func (a *authorizer) reload() error {
+	p, err := loadPolicy(a.file)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.policy = p
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *authorizer) reloadAndLog(reason string) {
+	if err := a.reload(); err != nil {
+		slog.Error("Failed to reload policy, keeping the previous one", "reason", reason, "error", err)
+		return
+	}
+	slog.Info("Reloaded policy", "reason", reason, "policy_file", a.file)
+}
+
+// authorize returns a PermissionDenied error unless the caller of ctx may
+// call method.
+func (a *authorizer) authorize(ctx context.Context, method string) error {
+	if isPublicMethod(method) {
+		return nil
+	}
+
+	a.mu.RLock()
+	p := a.policy
+	a.mu.RUnlock()
+
+	caller, ok := principalFromContext(ctx)
+	if p.allows(method, caller, ok) {
+		return nil
+	}
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "anonymous callers may not call %s", method)
+	}
+	return status.Errorf(codes.PermissionDenied, "%q may not call %s", caller.Name, method)
+}
+
+func (a *authorizer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authorizer) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
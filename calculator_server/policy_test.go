@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testPolicy = `
+rules:
+  - methods: ["/calculator.CalculatorService/Sum"]
+    principals: ["*"]
+    effect: allow
+  - methods: ["/calculator.CalculatorService/*"]
+    principals: [mallory]
+    effect: deny
+  - methods: ["/calculator.CalculatorService/*"]
+    roles: [admin]
+    effect: allow
+  - methods: ["/calculator.CalculatorService/Evaluate"]
+    claims: {tenant: acme}
+    effect: allow
+`
+
+func TestAuthorize(t *testing.T) {
+	file := writeFile(t, "policy.yaml", testPolicy)
+	a, err := newAuthorizer(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		sum      = "/calculator.CalculatorService/Sum"
+		evaluate = "/calculator.CalculatorService/Evaluate"
+		factor   = "/calculator.CalculatorService/Factorize"
+		health   = "/grpc.health.v1.Health/Check"
+	)
+	alice := principal{Name: "alice", Method: "api-key", Roles: []string{"admin"}}
+	bob := principal{Name: "bob", Method: "api-key", Roles: []string{"reader"}}
+	mallory := principal{Name: "mallory", Method: "api-key", Roles: []string{"admin"}}
+	acme := principal{Name: "carol", Method: "jwt", Claims: map[string]interface{}{"tenant": "acme"}}
+	groups := principal{Name: "dave", Method: "jwt", Claims: map[string]interface{}{"tenant": []interface{}{"other", "acme"}}}
+
+	type check struct {
+		caller *principal
+		method string
+		allow  bool
+	}
+	run := func(checks []check) {
+		t.Helper()
+		for _, c := range checks {
+			ctx, name := context.Background(), "anonymous"
+			if c.caller != nil {
+				ctx, name = context.WithValue(ctx, principalKey{}, *c.caller), c.caller.Name
+			}
+			err := a.authorize(ctx, c.method)
+			if c.allow && err != nil {
+				t.Errorf("%s calling %s: %v, want allowed", name, c.method, err)
+			}
+			if !c.allow && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("%s calling %s: %v, want %v", name, c.method, err, codes.PermissionDenied)
+			}
+		}
+	}
+	run([]check{
+		{&bob, sum, true},
+		{&mallory, sum, true},
+		{nil, sum, false},
+		// The deny for mallory comes before the allow for admins.
+		{&mallory, factor, false},
+		{&alice, factor, true},
+		{&bob, factor, false},
+		{&acme, evaluate, true},
+		{&groups, evaluate, true},
+		{&acme, factor, false},
+		{&bob, evaluate, false},
+		// Health checks are allowed whatever the policy.
+		{nil, health, true},
+	})
+
+	// A reload swaps the rules.
+	if err := os.WriteFile(file, []byte("default: allow\nrules:\n  - {methods: [\"/*/*\"], principals: [bob], effect: deny}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.reload(); err != nil {
+		t.Fatal(err)
+	}
+	after := []check{
+		{&bob, sum, false},
+		{&alice, factor, true},
+		{nil, factor, true},
+	}
+	run(after)
+
+	// A bad file keeps the policy in force.
+	for _, bad := range []string{
+		"default: maybe\n",
+		"rules:\n  - {methods: [\"/*/*\"], effect: permit}\n",
+		"rules:\n  - {effect: allow}\n",
+		"rules:\n  - {methods: [\"[\"], effect: allow}\n",
+		"unknown: field\n",
+	} {
+		if err := os.WriteFile(file, []byte(bad), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := a.reload(); err == nil {
+			t.Errorf("reloading %q succeeded", bad)
+		}
+		run(after)
+	}
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.reload(); err == nil {
+		t.Error("reloading a missing file succeeded")
+	}
+	run(after)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultPrimalityRounds and maxPrimalityRounds bound the Miller-Rabin
+	// rounds a request may ask for.
+	defaultPrimalityRounds = 20
+	maxPrimalityRounds     = 200
+
+	// sieveSegmentSize is how many numbers ListPrimes sieves at once; the
+	// primes of a segment are sent in one message.
+	sieveSegmentSize = 1 << 16
+)
+
+func (s *server) IsPrime(ctx context.Context, req *calculatorpb.IsPrimeRequest) (*calculatorpb.IsPrimeResponse, error) {
+	n, err := s.parseBigInt("number", req.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	rounds, err := primalityRounds(req.GetRounds())
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Sign() < 0 || n.IsUint64() {
+		return &calculatorpb.IsPrimeResponse{
+			Prime:   n.Sign() > 0 && isPrime(n.Uint64()),
+			Certain: true,
+		}, nil
+	}
+	// ProbablyPrime never takes a prime for a composite.
+	if !n.ProbablyPrime(rounds) {
+		return &calculatorpb.IsPrimeResponse{Certain: true}, nil
+	}
+	return &calculatorpb.IsPrimeResponse{
+		Prime:            true,
+		ErrorProbability: primalityError(rounds),
+	}, nil
+}
+
+func (s *server) NextPrime(ctx context.Context, req *calculatorpb.NextPrimeRequest) (*calculatorpb.NextPrimeResponse, error) {
+	return s.searchPrime(ctx, req, 1)
+}
+
+func (s *server) PrevPrime(ctx context.Context, req *calculatorpb.NextPrimeRequest) (*calculatorpb.NextPrimeResponse, error) {
+	return s.searchPrime(ctx, req, -1)
+}
+
+// searchPrime looks for the nearest prime above (step 1) or below (step -1)
+// the number of req.
+func (s *server) searchPrime(ctx context.Context, req *calculatorpb.NextPrimeRequest, step int64) (*calculatorpb.NextPrimeResponse, error) {
+	n, err := s.parseBigInt("number", req.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	rounds, err := primalityRounds(req.GetRounds())
+	if err != nil {
+		return nil, err
+	}
+	if step < 0 && n.Cmp(big.NewInt(2)) <= 0 {
+		return nil, requestError{
+			code:        codes.InvalidArgument,
+			reason:      "NO_SMALLER_PRIME",
+			field:       "number",
+			description: "must be greater than 2",
+			message:     fmt.Sprintf("There is no prime below %v", n),
+		}.err()
+	}
+	if step > 0 && n.Sign() < 0 {
+		n.SetInt64(0)
+	}
+	if step > 0 && n.BitLen() >= s.maxBits() {
+		return nil, s.resultTooLarge()
+	}
+
+	p, err := nearestPrime(ctx, n, step, rounds)
+	if err != nil {
+		return nil, err
+	}
+	res := &calculatorpb.NextPrimeResponse{Prime: p.String(), Certain: p.IsUint64()}
+	if !res.Certain {
+		res.ErrorProbability = primalityError(rounds)
+	}
+	return res, nil
+}
+
+// nearestPrime returns the first prime after n in the direction of step,
+// testing numbers above 64 bits with rounds of Miller-Rabin. Those
+// candidates are first checked against the small primes, using
+// the remainders of n so that no candidate is divided by them.
+func nearestPrime(ctx context.Context, n *big.Int, step int64, rounds int) (*big.Int, error) {
+	var rems []uint64
+	candidate := new(big.Int).Set(n)
+	for d := uint64(1); ; d++ {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		candidate.Add(candidate, big.NewInt(step))
+		if candidate.IsUint64() {
+			if isPrime(candidate.Uint64()) {
+				return candidate, nil
+			}
+			continue
+		}
+
+		if rems == nil {
+			rems = make([]uint64, len(smallPrimes))
+			p, r := new(big.Int), new(big.Int)
+			for i, small := range smallPrimes {
+				rems[i] = r.Mod(n, p.SetUint64(small)).Uint64()
+			}
+		}
+		if divisibleBySmallPrime(rems, d, step) {
+			continue
+		}
+		if candidate.ProbablyPrime(rounds) {
+			return candidate, nil
+		}
+	}
+}
+
+// divisibleBySmallPrime reports whether n + step*d is divisible by one of the
+// small primes, given the remainders rems of n.
+func divisibleBySmallPrime(rems []uint64, d uint64, step int64) bool {
+	for i, p := range smallPrimes {
+		offset := d % p
+		if step < 0 {
+			offset = p - offset
+		}
+		if (rems[i]+offset)%p == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *server) ListPrimes(req *calculatorpb.ListPrimesRequest, stream calculatorpb.CalculatorService_ListPrimesServer) error {
+	from, to := req.GetFrom(), req.GetTo()
+	if from > to {
+		return requestError{
+			code:        codes.InvalidArgument,
+			reason:      "EMPTY_RANGE",
+			field:       "to",
+			description: "must not be less than from",
+			message:     fmt.Sprintf("The range from %d to %d is empty", from, to),
+			metadata:    map[string]string{"from": strconv.FormatUint(from, 10), "to": strconv.FormatUint(to, 10)},
+		}.err()
+	}
+
+	// Send blocks while the client's flow-control window is full, so the
+	// server sieves no faster than the client reads and holds one segment
+	// at a time.
+	ctx := stream.Context()
+	composite := make([]bool, sieveSegmentSize)
+	var primes []uint64
+	for lo := from; ; lo += sieveSegmentSize {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		hi := lo + sieveSegmentSize - 1
+		if hi > to || hi < lo {
+			hi = to
+		}
+		primes = sieveSegment(lo, hi, composite, primes[:0])
+		if len(primes) > 0 {
+			if err := stream.Send(&calculatorpb.ListPrimesResponse{Primes: primes}); err != nil {
+				return streamError(ctx, err, "sending primes")
+			}
+		}
+		if hi == to {
+			return nil
+		}
+	}
+}
+
+// sieveSegment appends the primes from lo to hi to primes. It crosses out
+// the multiples of the small primes, which finds every prime below
+// smallPrimeLimit squared; above that, what is left is tested with
+// Miller-Rabin. composite must hold at least hi-lo+1 entries.
+func sieveSegment(lo, hi uint64, composite []bool, primes []uint64) []uint64 {
+	size := hi - lo + 1
+	clear(composite[:size])
+	for _, p := range smallPrimes {
+		if p*p > hi {
+			break
+		}
+		first := lo + (p-lo%p)%p
+		if first < p*p {
+			first = p * p
+		}
+		for i := first - lo; i < size; i += p {
+			composite[i] = true
+		}
+	}
+	for i := uint64(0); i < size; i++ {
+		n := lo + i
+		if n < 2 || composite[i] {
+			continue
+		}
+		if n >= smallPrimeLimit*smallPrimeLimit && !isPrime(n) {
+			continue
+		}
+		primes = append(primes, n)
+	}
+	return primes
+}
+
+func primalityRounds(rounds uint32) (int, error) {
+	if rounds == 0 {
+		return defaultPrimalityRounds, nil
+	}
+	if rounds > maxPrimalityRounds {
+		return 0, requestError{
+			code:        codes.InvalidArgument,
+			reason:      "TOO_MANY_ROUNDS",
+			field:       "rounds",
+			description: fmt.Sprintf("must be at most %d", maxPrimalityRounds),
+			message:     fmt.Sprintf("At most %d Miller-Rabin rounds are allowed", maxPrimalityRounds),
+		}.err()
+	}
+	return int(rounds), nil
+}
+
+// primalityError is the probability that rounds of Miller-Rabin with random
+// bases take a composite for a prime, at most 4^-rounds.
+func primalityError(rounds int) float64 {
+	return math.Pow(4, -float64(rounds))
+}
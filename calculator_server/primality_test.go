@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSieveSegment(t *testing.T) {
+	tests := []struct{ lo, hi uint64 }{
+		{0, 0},
+		{0, 1},
+		{0, 2},
+		{0, 100000},
+		{4, 4},
+		{65521, 65537},
+		// Across smallPrimeLimit squared, above which Miller-Rabin is used.
+		{1<<32 - 20000, 1<<32 + 20000},
+		{1<<61 - 10000, 1<<61 + 10000},
+		{math.MaxUint64 - sieveSegmentSize + 1, math.MaxUint64},
+	}
+	composite := make([]bool, sieveSegmentSize*2)
+	for _, tt := range tests {
+		var want []uint64
+		for n := tt.lo; n <= tt.hi && n >= tt.lo; n++ {
+			if isPrime(n) {
+				want = append(want, n)
+			}
+		}
+		// Reused buffers must not carry over marks or primes.
+		composite[0] = true
+		got := sieveSegment(tt.lo, tt.hi, composite, []uint64{7}[:0])
+		if !slices.Equal(got, want) {
+			t.Errorf("sieveSegment(%d, %d) found %d primes, want %d: %v", tt.lo, tt.hi, len(got), len(want), got)
+		}
+	}
+}
+
+func TestNearestPrime(t *testing.T) {
+	pow2 := func(k uint) *big.Int { return new(big.Int).Lsh(bigOne, k) }
+	tests := []struct {
+		n    *big.Int
+		step int64
+		want *big.Int
+	}{
+		{big.NewInt(0), 1, big.NewInt(2)},
+		{big.NewInt(2), 1, big.NewInt(3)},
+		{big.NewInt(3), -1, big.NewInt(2)},
+		{big.NewInt(24), 1, big.NewInt(29)},
+		{big.NewInt(24), -1, big.NewInt(23)},
+		{big.NewInt(1<<31 - 1), 1, big.NewInt(1<<31 + 11)},
+		// Across 2^64: 2^64 - 59 and 2^64 + 13 are consecutive primes.
+		{add(pow2(64), -59), 1, add(pow2(64), 13)},
+		{add(pow2(64), 13), -1, add(pow2(64), -59)},
+		{pow2(64), -1, add(pow2(64), -59)},
+		{pow2(64), 1, add(pow2(64), 13)},
+		{pow2(127), -1, add(pow2(127), -1)},
+		{add(pow2(89), -2), 1, add(pow2(89), -1)},
+		// 10^30 + 57 and 10^30 - 11 surround 10^30.
+		{new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil), 1, add(new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil), 57)},
+		{new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil), -1, add(new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil), -11)},
+	}
+	for _, tt := range tests {
+		got, err := nearestPrime(context.Background(), new(big.Int).Set(tt.n), tt.step, defaultPrimalityRounds)
+		if err != nil || got.Cmp(tt.want) != 0 {
+			t.Errorf("nearestPrime(%v, %d) = %v, %v, want %v", tt.n, tt.step, got, err, tt.want)
+		}
+	}
+}
+
+// TestNearestPrimeAgainstBig checks that the candidates skipped for a small
+// prime factor are composite, going up and down from large numbers.
+func TestNearestPrimeAgainstBig(t *testing.T) {
+	n := new(big.Int).Exp(big.NewInt(7), big.NewInt(60), nil)
+	for _, step := range []int64{1, -1} {
+		p := new(big.Int).Set(n)
+		for i := 0; i < 50; i++ {
+			next, err := nearestPrime(context.Background(), p, step, defaultPrimalityRounds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for c := add(p, step); c.Cmp(next) != 0; c.Add(c, big.NewInt(step)) {
+				if c.ProbablyPrime(defaultPrimalityRounds) {
+					t.Fatalf("nearestPrime(%v, %d) = %v skips the prime %v", p, step, next, c)
+				}
+			}
+			p = next
+		}
+	}
+}
+
+// add returns n + d.
+func add(n *big.Int, d int64) *big.Int {
+	return new(big.Int).Add(n, big.NewInt(d))
+}
+
+func TestNearestPrimeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := nearestPrime(ctx, big.NewInt(24), 1, defaultPrimalityRounds)
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("nearestPrime with a canceled context = %v, want %v", err, codes.Canceled)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"math/bits"
+
+	"google.golang.org/grpc/status"
+)
+
+// smallPrimeLimit bounds the primes found by trial division. A number without
+// a factor below it and smaller than its square is prime.
+const smallPrimeLimit = 1 << 16
+
+// smallPrimes are the primes below smallPrimeLimit, in ascending order.
+var smallPrimes = sieve(smallPrimeLimit)
+
+// rhoBatch is how many Pollard's rho steps are taken between gcds and calls
+// of its tick function.
+const rhoBatch = 128
+
+// sieve returns the primes below limit using the sieve of Eratosthenes.
+func sieve(limit uint64) []uint64 {
+	composite := make([]bool, limit)
+	var primes []uint64
+	for i := uint64(2); i < limit; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for j := i * i; j < limit; j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
+
+// factorize calls emit with every prime factor of n, repeated as often as it
+// divides n. Factors below smallPrimeLimit come first in ascending order; the
+// others follow as they are found. ctx is checked between steps.
+func factorize(ctx context.Context, n uint64, emit func(factor uint64) error) error {
+	for _, p := range smallPrimes {
+		if p*p > n {
+			break
+		}
+		for n%p == 0 {
+			if err := emit(p); err != nil {
+				return err
+			}
+			n /= p
+		}
+	}
+	if n == 1 {
+		return nil
+	}
+	return factorizeLarge(ctx, n, emit)
+}
+
+// factorizeLarge factors n, which has no prime factor below smallPrimeLimit.
+func factorizeLarge(ctx context.Context, n uint64, emit func(factor uint64) error) error {
+	if n < smallPrimeLimit*smallPrimeLimit || isPrime(n) {
+		return emit(n)
+	}
+	d, err := pollardRho(n, func(int) error {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := factorizeLarge(ctx, d, emit); err != nil {
+		return err
+	}
+	return factorizeLarge(ctx, n/d, emit)
+}
+
+// isPrime reports whether n is prime. The Miller–Rabin test with the first
+// twelve primes as bases is deterministic for every 64-bit number.
+func isPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	bases := smallPrimes[:12]
+	for _, p := range bases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	d, r := n-1, 0
+	for d%2 == 0 {
+		d /= 2
+		r++
+	}
+	for _, a := range bases {
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		composite := true
+		for i := 1; i < r; i++ {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// pollardRho returns a non-trivial factor of the odd composite n, using
+// Brent's variant of Pollard's rho algorithm. It calls tick with the number
+// of steps taken since the last call and gives up if tick returns an error.
+func pollardRho(n uint64, tick func(steps int) error) (uint64, error) {
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 { return (mulMod(x, x, n) + c) % n }
+
+		y, x, ys := uint64(2), uint64(2), uint64(2)
+		g, q := uint64(1), uint64(1)
+		for r := 1; g == 1; r *= 2 {
+			x = y
+			for i := 0; i < r; i++ {
+				if i%rhoBatch == 0 {
+					if err := tick(min(rhoBatch, r-i)); err != nil {
+						return 0, err
+					}
+				}
+				y = f(y)
+			}
+			for k := 0; k < r && g == 1; k += rhoBatch {
+				if err := tick(min(rhoBatch, r-k)); err != nil {
+					return 0, err
+				}
+				ys = y
+				for i := 0; i < rhoBatch && i < r-k; i++ {
+					y = f(y)
+					q = mulMod(q, absDiff(x, y), n)
+				}
+				g = gcd(q, n)
+			}
+		}
+
+		// The batch overshot: retrace it one step at a time.
+		if g == n {
+			for g = 1; g == 1; {
+				ys = f(ys)
+				g = gcd(absDiff(x, ys), n)
+			}
+		}
+		if g != n {
+			return g, nil
+		}
+	}
+}
+
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi%m, lo, m)
+	return rem
+}
+
+func powMod(base, exp, m uint64) uint64 {
+	result := uint64(1)
+	base %= m
+	for exp > 0 {
+		if exp&1 == 1 {
+			result = mulMod(result, base, m)
+		}
+		base = mulMod(base, base, m)
+		exp >>= 1
+	}
+	return result
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsPrime(t *testing.T) {
+	tests := []struct {
+		n     uint64
+		prime bool
+	}{
+		{0, false},
+		{1, false},
+		{2, true},
+		{3, true},
+		{4, false},
+		{37, true},
+		{41, true},
+		{65537, true},
+		{1<<31 - 1, true},
+		{1<<32 - 5, true},
+		{1<<61 - 1, true},
+		{math.MaxUint64 - 58, true},
+		{math.MaxUint64, false},
+		// Squares of primes, the largest below 2^64.
+		{65537 * 65537, false},
+		{(1<<32 - 5) * (1<<32 - 5), false},
+		// Carmichael numbers, which fool the Fermat test for every base.
+		{561, false},
+		{1105, false},
+		{1729, false},
+		{2465, false},
+		{6601, false},
+		{8911, false},
+		{41041, false},
+		{825265, false},
+		{321197185, false},
+		{9999109081, false},
+		// Strong pseudoprimes to the first 4, 5, 6, 7 and 9 prime bases.
+		{3215031751, false},
+		{2152302898747, false},
+		{3474749660383, false},
+		{341550071728321, false},
+		{3825123056546413051, false},
+		// Semiprimes near 2^64.
+		{(1<<32 - 5) * (1<<32 - 17), false},
+		{16777213 * 1099511824357, false},
+	}
+	for _, tt := range tests {
+		if got := isPrime(tt.n); got != tt.prime {
+			t.Errorf("isPrime(%d) = %v, want %v", tt.n, got, tt.prime)
+		}
+	}
+}
+
+func TestIsPrimeAgainstBig(t *testing.T) {
+	for n := uint64(0); n < 100000; n++ {
+		if got, want := isPrime(n), new(big.Int).SetUint64(n).ProbablyPrime(0); got != want {
+			t.Fatalf("isPrime(%d) = %v, want %v", n, got, want)
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		n := rng.Uint64() | 1
+		if got, want := isPrime(n), new(big.Int).SetUint64(n).ProbablyPrime(20); got != want {
+			t.Fatalf("isPrime(%d) = %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestMulMod(t *testing.T) {
+	tests := []struct{ a, b, m uint64 }{
+		{0, math.MaxUint64, 7},
+		{3, 5, 7},
+		{math.MaxUint64, math.MaxUint64, math.MaxUint64},
+		{math.MaxUint64, math.MaxUint64, math.MaxUint64 - 58},
+		{math.MaxUint64 - 59, math.MaxUint64 - 60, math.MaxUint64 - 58},
+		{1 << 63, 1 << 63, 1<<63 + 1},
+		{1<<32 - 5, 1<<32 - 17, 1},
+		{123456789123456789, 987654321987654321, 1<<61 - 1},
+	}
+	for _, tt := range tests {
+		want := new(big.Int).SetUint64(tt.a)
+		want.Mul(want, new(big.Int).SetUint64(tt.b))
+		want.Mod(want, new(big.Int).SetUint64(tt.m))
+		if got := mulMod(tt.a, tt.b, tt.m); got != want.Uint64() {
+			t.Errorf("mulMod(%d, %d, %d) = %d, want %d", tt.a, tt.b, tt.m, got, want)
+		}
+	}
+}
+
+func TestPollardRho(t *testing.T) {
+	tests := []uint64{
+		15,
+		561,
+		65537 * 65537,
+		1000003 * 1000033,
+		3825123056546413051,
+		(1<<32 - 5) * (1<<32 - 17),
+		(1<<32 - 5) * (1<<32 - 5),
+		16777213 * 1099511824357,
+	}
+	for _, n := range tests {
+		steps := 0
+		d, err := pollardRho(n, func(s int) error {
+			steps += s
+			return nil
+		})
+		if err != nil || d <= 1 || d >= n || n%d != 0 {
+			t.Errorf("pollardRho(%d) = %d, %v, want a non-trivial factor", n, d, err)
+		}
+		if steps == 0 {
+			t.Errorf("pollardRho(%d) never called tick", n)
+		}
+	}
+}
+
+func TestPollardRhoGivesUp(t *testing.T) {
+	errStop := errors.New("stop")
+	const limit = 1000
+	// Its factors are near 2^32, so it takes tens of thousands of steps.
+	n := uint64((1<<32 - 5) * (1<<32 - 17))
+	steps := 0
+	_, err := pollardRho(n, func(s int) error {
+		if steps += s; steps > limit {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("pollardRho(%d) = %v after %d steps, want the error of tick", n, err, steps)
+	}
+	if steps > limit+rhoBatch {
+		t.Errorf("pollardRho(%d) took %d steps, want at most %d", n, steps, limit+rhoBatch)
+	}
+}
+
+func TestFactorize(t *testing.T) {
+	tests := []struct {
+		n       uint64
+		factors []uint64
+	}{
+		{1, nil},
+		{2, []uint64{2}},
+		{360, []uint64{2, 2, 2, 3, 3, 5}},
+		{1 << 20, []uint64{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2}},
+		{math.MaxUint64, []uint64{3, 5, 17, 257, 641, 65537, 6700417}},
+		{math.MaxUint64 - 58, []uint64{math.MaxUint64 - 58}},
+		{(1<<32 - 5) * (1<<32 - 17), []uint64{1<<32 - 17, 1<<32 - 5}},
+		{(1<<32 - 5) * (1<<32 - 5), []uint64{1<<32 - 5, 1<<32 - 5}},
+		{3825123056546413051, []uint64{149491, 747451, 34233211}},
+	}
+	for _, tt := range tests {
+		var factors []uint64
+		err := factorize(context.Background(), tt.n, func(p uint64) error {
+			factors = append(factors, p)
+			return nil
+		})
+		slices.Sort(factors)
+		if err != nil || !slices.Equal(factors, tt.factors) {
+			t.Errorf("factorize(%d) = %v, %v, want %v", tt.n, factors, err, tt.factors)
+		}
+	}
+}
+
+func TestFactorizeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n := uint64((1<<32 - 5) * (1<<32 - 17))
+	err := factorize(ctx, n, func(uint64) error { return nil })
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("factorize(%d) with a canceled context = %v, want %v", n, err, codes.Canceled)
+	}
+}
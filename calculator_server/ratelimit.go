@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// limiterIdleTimeout is how long the state of a client is kept after its last
+// call ended.
+const limiterIdleTimeout = 10 * time.Minute
+
+// rateLimiter enforces the rate limits and concurrency quotas of each client.
+type rateLimiter struct {
+	rules []rateLimitConfig
+
+	mu        sync.Mutex
+	clients   map[limitKey]*clientLimit
+	lastSweep time.Time
+}
+
+type limitKey struct {
+	rule   int
+	client string
+}
+
+// clientLimit is the state of one client under one rule.
+type clientLimit struct {
+	limiter  *rate.Limiter
+	active   int
+	lastSeen time.Time
+}
+
+func newRateLimiter(rules []rateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		rules:     rules,
+		clients:   map[limitKey]*clientLimit{},
+		lastSweep: time.Now(),
+	}
+}
+
+// ruleFor returns the index of the first rule that covers method, or -1.
+func (l *rateLimiter) ruleFor(method string) int {
+	for i, rule := range l.rules {
+		for _, pattern := range rule.Methods {
+			if matched, _ := path.Match(pattern, method); matched {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// clientKeyFromContext identifies the caller for rate limiting: by its
+// authenticated principal, else by its client certificate, else by its IP
+// address.
+func clientKeyFromContext(ctx context.Context) string {
+	if p, ok := principalFromContext(ctx); ok {
+		return "principal:" + p.Name
+	}
+	if id, ok := clientIdentityFromContext(ctx); ok {
+		return "cert:" + id.String()
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if addr, ok := p.Addr.(*net.TCPAddr); ok {
+			return "ip:" + addr.IP.String()
+		}
+		return "addr:" + p.Addr.String()
+	}
+	return "unknown"
+}
+
+// acquire admits a call to method, or returns a ResourceExhausted error if the
+// caller is over its limits. release must be called when the call ends.
+func (l *rateLimiter) acquire(ctx context.Context, method string) (release func(), err error) {
+	i := l.ruleFor(method)
+	if i < 0 {
+		return func() {}, nil
+	}
+	rule := l.rules[i]
+	client := clientKeyFromContext(ctx)
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	key := limitKey{i, client}
+	c := l.clients[key]
+	if c == nil {
+		c = &clientLimit{}
+		if rule.Rate > 0 {
+			c.limiter = rate.NewLimiter(rate.Limit(rule.Rate), rule.Burst)
+		}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+
+	if rule.MaxConcurrent > 0 && c.active >= rule.MaxConcurrent {
+		return nil, quotaError(client,
+			fmt.Sprintf("too many concurrent calls to %s: at most %d allowed", method, rule.MaxConcurrent),
+			fmt.Sprintf("%d concurrent calls to %s", rule.MaxConcurrent, method), 0)
+	}
+	if c.limiter != nil {
+		r := c.limiter.ReserveN(now, 1)
+		if delay := r.DelayFrom(now); delay > 0 {
+			r.CancelAt(now)
+			return nil, quotaError(client,
+				fmt.Sprintf("rate limit exceeded for %s: %v calls per second allowed, retry in %v", method, rule.Rate, delay.Round(time.Millisecond)),
+				fmt.Sprintf("%v calls per second to %s", rule.Rate, method), delay)
+		}
+	}
+
+	c.active++
+	return func() {
+		l.mu.Lock()
+		c.active--
+		c.lastSeen = time.Now()
+		l.mu.Unlock()
+	}, nil
+}
+
+// sweep forgets clients that have been idle for limiterIdleTimeout. Their
+// buckets are full again by then, so nothing is lost. l.mu must be held.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterIdleTimeout {
+		return
+	}
+	l.lastSweep = now
+	for key, c := range l.clients {
+		if c.active == 0 && now.Sub(c.lastSeen) >= limiterIdleTimeout {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// quotaError returns a ResourceExhausted error for client, which exceeded the
+// quota in description, telling it when to retry if retryAfter is known.
+func quotaError(client, message, description string, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, message)
+	details := []protoadapt.MessageV1{
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     client,
+				Description: description,
+			}},
+		},
+	}
+	if retryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails.Err()
+	}
+	return st.Err()
+}
+
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	release, err := l.acquire(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return handler(ctx, req)
+}
+
+func (l *rateLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	release, err := l.acquire(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return handler(srv, ss)
+}
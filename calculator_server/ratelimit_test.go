@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// quotaDetails returns the code of err and its QuotaFailure and RetryInfo
+// details.
+func quotaDetails(err error) (codes.Code, *errdetails.QuotaFailure, *errdetails.RetryInfo) {
+	st := status.Convert(err)
+	var quota *errdetails.QuotaFailure
+	var retry *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.QuotaFailure:
+			quota = d
+		case *errdetails.RetryInfo:
+			retry = d
+		}
+	}
+	return st.Code(), quota, retry
+}
+
+func TestRateLimiterRate(t *testing.T) {
+	const sum = "/calculator.CalculatorService/Sum"
+	// One call every 100 seconds after a burst of 2.
+	l := newRateLimiter([]rateLimitConfig{{Methods: []string{sum}, Rate: 0.01, Burst: 2}})
+	alice := context.WithValue(context.Background(), principalKey{}, principal{Name: "alice"})
+	bob := context.WithValue(context.Background(), principalKey{}, principal{Name: "bob"})
+
+	for i := 0; i < 2; i++ {
+		release, err := l.acquire(alice, sum)
+		if err != nil {
+			t.Fatalf("call %d within the burst: %v", i+1, err)
+		}
+		release()
+	}
+	_, err := l.acquire(alice, sum)
+	code, quota, retry := quotaDetails(err)
+	if code != codes.ResourceExhausted {
+		t.Fatalf("call over the rate = %v, want %v", err, codes.ResourceExhausted)
+	}
+	if len(quota.GetViolations()) != 1 || quota.GetViolations()[0].GetSubject() != "principal:alice" {
+		t.Errorf("call over the rate has quota failure %v, want one violation by principal:alice", quota)
+	}
+	if d := retry.GetRetryDelay().AsDuration(); d <= 0 || d > 100*time.Second {
+		t.Errorf("call over the rate has retry delay %v, want up to 100s", d)
+	}
+
+	// Other clients and methods have buckets of their own.
+	if _, err := l.acquire(bob, sum); err != nil {
+		t.Errorf("call by another client: %v", err)
+	}
+	if _, err := l.acquire(alice, "/calculator.CalculatorService/Evaluate"); err != nil {
+		t.Errorf("call to a method without a rule: %v", err)
+	}
+}
+
+func TestRateLimiterConcurrency(t *testing.T) {
+	const stream = "/calculator.CalculatorService/FindMaximum"
+	l := newRateLimiter([]rateLimitConfig{{Methods: []string{"/calculator.CalculatorService/*"}, MaxConcurrent: 2}})
+	ctx := context.WithValue(context.Background(), principalKey{}, principal{Name: "alice"})
+
+	first, err := l.acquire(ctx, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.acquire(ctx, stream); err != nil {
+		t.Fatal(err)
+	}
+	_, err = l.acquire(ctx, stream)
+	code, quota, retry := quotaDetails(err)
+	if code != codes.ResourceExhausted || len(quota.GetViolations()) != 1 {
+		t.Fatalf("third concurrent call = %v, want %v with a quota failure", err, codes.ResourceExhausted)
+	}
+	// The wait depends on when a call ends, so there is no retry delay.
+	if retry != nil {
+		t.Errorf("third concurrent call has retry info %v, want none", retry)
+	}
+
+	first()
+	if _, err := l.acquire(ctx, stream); err != nil {
+		t.Errorf("call after another ended: %v", err)
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	const sum = "/calculator.CalculatorService/Sum"
+	l := newRateLimiter([]rateLimitConfig{{Methods: []string{sum}, Rate: 1, Burst: 1, MaxConcurrent: 1}})
+	busy := context.WithValue(context.Background(), principalKey{}, principal{Name: "busy"})
+	idle := context.WithValue(context.Background(), principalKey{}, principal{Name: "idle"})
+
+	if _, err := l.acquire(busy, sum); err != nil {
+		t.Fatal(err)
+	}
+	release, err := l.acquire(idle, sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	l.mu.Lock()
+	l.sweep(time.Now().Add(2 * limiterIdleTimeout))
+	_, kept := l.clients[limitKey{0, "principal:busy"}]
+	_, stale := l.clients[limitKey{0, "principal:idle"}]
+	l.mu.Unlock()
+	if !kept {
+		t.Error("sweep dropped a client with a call open")
+	}
+	if stale {
+		t.Error("sweep kept an idle client")
+	}
+
+	// The open call still counts against the busy client.
+	if _, err := l.acquire(busy, sum); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second concurrent call after a sweep = %v, want %v", err, codes.ResourceExhausted)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay lets a rotation that rewrites several files settle before they
+// are loaded again.
+const reloadDelay = 200 * time.Millisecond
+
+// watchFiles calls reload whenever one of files changes. The directories are
+// watched rather than the files themselves so that updates which replace the
+// files are noticed too. An update may not touch the files' names at all: a
+// Kubernetes secret volume swaps a ..data symlink that the files point
+// through. So on any event in a directory, the files are resolved again and
+// a change of the file a name leads to counts as a change.
+func watchFiles(files []string, reload func(reason string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// watched maps each file to the file it resolves to through symlinks.
+	watched := map[string]string{}
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			watcher.Close()
+			return err
+		}
+		watched[abs] = resolveSymlinks(abs)
+		if err := watcher.Add(filepath.Dir(abs)); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+
+	go func() {
+		var pending <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				_, named := watched[filepath.Clean(event.Name)]
+				changed := named && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) != 0
+				for file, target := range watched {
+					if now := resolveSymlinks(file); now != target {
+						watched[file] = now
+						changed = true
+					}
+				}
+				if changed {
+					pending = time.After(reloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Warn("File watcher error", "files", fmt.Sprint(files), "error", err)
+			case <-pending:
+				pending = nil
+				reload("file changed")
+			}
+		}
+	}()
+	return nil
+}
+
+// resolveSymlinks returns the file that file leads to through symlinks, or ""
+// if it cannot be resolved, for example while it is being replaced.
+func resolveSymlinks(file string) string {
+	target, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return ""
+	}
+	return target
+}
+
+// reloadOnSignal calls reload every time one of sig is received.
+func reloadOnSignal(reload func(reason string), sig ...os.Signal) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sig...)
+	go func() {
+		for s := range c {
+			reload(s.String())
+		}
+	}()
+}
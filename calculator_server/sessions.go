@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+// sessionSweepInterval is how often the session store looks for expired
+// sessions to forget.
+const sessionSweepInterval = time.Minute
+
+// session is the state Evaluate keeps between calls: variables and ans.
+type session struct {
+	// owner is the principal that created the session, or empty if it was
+	// created anonymously. Only the owner can use the session.
+	owner string
+	// lastUsed is guarded by the store's mutex.
+	lastUsed time.Time
+
+	mu     sync.Mutex
+	vars   map[string]float64
+	ans    float64
+	hasAns bool
+}
+
+// sessionStore holds the sessions, forgetting those unused for longer than
+// their TTL.
+type sessionStore struct {
+	cfg sessionsConfig
+	// now returns the current time; tests replace it to expire sessions.
+	now func() time.Time
+
+	mu        sync.Mutex
+	sessions  map[string]*session
+	lastSweep time.Time
+}
+
+func newSessionStore(cfg sessionsConfig) *sessionStore {
+	return &sessionStore{
+		cfg:       cfg,
+		now:       time.Now,
+		sessions:  map[string]*session{},
+		lastSweep: time.Now(),
+	}
+}
+
+// sessionOwner identifies the caller of ctx as the owner of a session.
+func sessionOwner(ctx context.Context) string {
+	if p, ok := principalFromContext(ctx); ok {
+		return p.Name
+	}
+	return ""
+}
+
+func (st *sessionStore) create(ctx context.Context) (string, error) {
+	b := make([]byte, 16)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+	now := st.now()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sweep(now)
+	if len(st.sessions) >= st.cfg.MaxSessions {
+		return "", requestError{
+			code:     codes.ResourceExhausted,
+			reason:   "TOO_MANY_SESSIONS",
+			message:  fmt.Sprintf("The server already keeps %d sessions, try again later", st.cfg.MaxSessions),
+			metadata: map[string]string{"max_sessions": strconv.Itoa(st.cfg.MaxSessions)},
+		}.err()
+	}
+	st.sessions[id] = &session{
+		owner:    sessionOwner(ctx),
+		lastUsed: now,
+		vars:     map[string]float64{},
+	}
+	return id, nil
+}
+
+// get returns the session id for the caller of ctx and extends its life.
+// A session of another caller is reported as not found.
+func (st *sessionStore) get(ctx context.Context, id string) (*session, error) {
+	now := st.now()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sweep(now)
+	s, ok := st.sessions[id]
+	if !ok || s.owner != sessionOwner(ctx) {
+		return nil, sessionNotFound(id)
+	}
+	if now.Sub(s.lastUsed) >= st.cfg.TTL {
+		delete(st.sessions, id)
+		return nil, sessionNotFound(id)
+	}
+	s.lastUsed = now
+	return s, nil
+}
+
+func (st *sessionStore) delete(ctx context.Context, id string) error {
+	if _, err := st.get(ctx, id); err != nil {
+		return err
+	}
+	st.mu.Lock()
+	delete(st.sessions, id)
+	st.mu.Unlock()
+	return nil
+}
+
+// sweep forgets expired sessions. st.mu must be held.
+func (st *sessionStore) sweep(now time.Time) {
+	if now.Sub(st.lastSweep) < sessionSweepInterval {
+		return
+	}
+	st.lastSweep = now
+	for id, s := range st.sessions {
+		if now.Sub(s.lastUsed) >= st.cfg.TTL {
+			delete(st.sessions, id)
+		}
+	}
+}
+
+func sessionNotFound(id string) error {
+	return requestError{
+		code:        codes.NotFound,
+		reason:      "SESSION_NOT_FOUND",
+		field:       "session_id",
+		description: "does not exist or has expired",
+		message:     fmt.Sprintf("Session %q does not exist or has expired", id),
+	}.err()
+}
+
+func (s *server) CreateSession(ctx context.Context, req *calculatorpb.CreateSessionRequest) (*calculatorpb.CreateSessionResponse, error) {
+	id, err := s.sessions.create(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.CreateSessionResponse{
+		SessionId:  id,
+		TtlSeconds: uint32(s.sessions.cfg.TTL.Seconds()),
+	}, nil
+}
+
+func (s *server) ListVariables(ctx context.Context, req *calculatorpb.ListVariablesRequest) (*calculatorpb.ListVariablesResponse, error) {
+	sess, err := s.sessions.get(ctx, req.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	res := &calculatorpb.ListVariablesResponse{}
+	for name, value := range sess.vars {
+		res.Variables = append(res.Variables, &calculatorpb.Variable{Name: name, Value: value})
+	}
+	if sess.hasAns {
+		res.Variables = append(res.Variables, &calculatorpb.Variable{Name: "ans", Value: sess.ans})
+	}
+	sort.Slice(res.Variables, func(i, j int) bool {
+		return res.Variables[i].Name < res.Variables[j].Name
+	})
+	return res, nil
+}
+
+func (s *server) DeleteSession(ctx context.Context, req *calculatorpb.DeleteSessionRequest) (*calculatorpb.DeleteSessionResponse, error) {
+	if err := s.sessions.delete(ctx, req.GetSessionId()); err != nil {
+		return nil, err
+	}
+	return &calculatorpb.DeleteSessionResponse{}, nil
+}
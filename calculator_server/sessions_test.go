@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+// newTestClock makes st use a clock that only moves when the returned
+// function advances it.
+func newTestClock(st *sessionStore) func(time.Duration) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	st.now = func() time.Time { return now }
+	st.lastSweep = now
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+// variables returns the variables of session id as a map.
+func variables(t *testing.T, s *server, ctx context.Context, id string) map[string]float64 {
+	t.Helper()
+	res, err := s.ListVariables(ctx, &calculatorpb.ListVariablesRequest{SessionId: id})
+	if err != nil {
+		t.Fatalf("ListVariables: %v", err)
+	}
+	vars := map[string]float64{}
+	for i, v := range res.GetVariables() {
+		if i > 0 && res.GetVariables()[i-1].GetName() >= v.GetName() {
+			t.Errorf("ListVariables is not sorted by name: %v", res.GetVariables())
+		}
+		vars[v.GetName()] = v.GetValue()
+	}
+	return vars
+}
+
+func TestSessions(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	created, err := s.CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := created.GetSessionId()
+	if want := uint32(s.sessions.cfg.TTL.Seconds()); created.GetTtlSeconds() != want {
+		t.Errorf("CreateSession returned a TTL of %ds, want %ds", created.GetTtlSeconds(), want)
+	}
+	if vars := variables(t, s, ctx, id); len(vars) != 0 {
+		t.Errorf("a new session has variables %v", vars)
+	}
+
+	tests := []struct {
+		expression string
+		want       float64
+		// reason is the error reason, or empty if the call succeeds.
+		reason string
+	}{
+		{"x = 5; y = x * 2", 10, ""},
+		{"ans + y", 20, ""},
+		{"x * ans", 100, ""},
+		// A failed call leaves the session as it was.
+		{"z = 1; 1 / 0", 0, "DIVISION_BY_ZERO"},
+		{"z", 0, "UNKNOWN_NAME"},
+		{"ans", 100, ""},
+		{"x = x + 1", 6, ""},
+	}
+	for _, tt := range tests {
+		res, err := s.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: tt.expression, SessionId: id})
+		_, reason, _ := errorInfo(err)
+		if reason != tt.reason || tt.reason == "" && res.GetResult() != tt.want {
+			t.Errorf("Evaluate(%q) = %v, %v, want %v %s", tt.expression, res.GetResult(), err, tt.want, tt.reason)
+		}
+	}
+	want := map[string]float64{"x": 6, "y": 10, "ans": 6}
+	if vars := variables(t, s, ctx, id); len(vars) != len(want) || vars["x"] != 6 || vars["y"] != 10 || vars["ans"] != 6 {
+		t.Errorf("the session has variables %v, want %v", vars, want)
+	}
+
+	// Without the session, nothing carries over.
+	if _, err := s.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "x"}); err == nil {
+		t.Error("Evaluate(x) without the session succeeded")
+	}
+
+	// Another caller cannot see the session.
+	other := context.WithValue(ctx, principalKey{}, principal{Name: "mallory"})
+	for name, call := range map[string]func(context.Context) error{
+		"Evaluate": func(ctx context.Context) error {
+			_, err := s.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "x", SessionId: id})
+			return err
+		},
+		"ListVariables": func(ctx context.Context) error {
+			_, err := s.ListVariables(ctx, &calculatorpb.ListVariablesRequest{SessionId: id})
+			return err
+		},
+		"DeleteSession": func(ctx context.Context) error {
+			_, err := s.DeleteSession(ctx, &calculatorpb.DeleteSessionRequest{SessionId: id})
+			return err
+		},
+	} {
+		if code, reason, _ := errorInfo(call(other)); code != codes.NotFound || reason != "SESSION_NOT_FOUND" {
+			t.Errorf("%s by another caller = %v %s, want %v SESSION_NOT_FOUND", name, code, reason, codes.NotFound)
+		}
+	}
+
+	if _, err := s.DeleteSession(ctx, &calculatorpb.DeleteSessionRequest{SessionId: id}); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+	_, err = s.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "x", SessionId: id})
+	if code, reason, _ := errorInfo(err); code != codes.NotFound || reason != "SESSION_NOT_FOUND" {
+		t.Errorf("Evaluate in a deleted session = %v, want %v SESSION_NOT_FOUND", err, codes.NotFound)
+	}
+	if _, err := s.DeleteSession(ctx, &calculatorpb.DeleteSessionRequest{SessionId: id}); err == nil {
+		t.Error("deleting a session twice succeeded")
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	s := newTestServer(t)
+	advance := newTestClock(s.sessions)
+	ttl := s.sessions.cfg.TTL
+	ctx := context.Background()
+
+	create := func() string {
+		t.Helper()
+		res, err := s.CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.GetSessionId()
+	}
+	use := func(id string) error {
+		_, err := s.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "x = 1", SessionId: id})
+		return err
+	}
+
+	used, unused := create(), create()
+	// Each use extends the life of a session by the TTL.
+	for i := 0; i < 3; i++ {
+		advance(ttl - time.Second)
+		if err := use(used); err != nil {
+			t.Fatalf("using a session after %v: %v", ttl-time.Second, err)
+		}
+	}
+	if err := use(unused); err == nil {
+		t.Errorf("a session unused for %v is still there", 3*(ttl-time.Second))
+	}
+	advance(ttl)
+	if code, reason, _ := errorInfo(use(used)); code != codes.NotFound || reason != "SESSION_NOT_FOUND" {
+		t.Errorf("using a session unused for the TTL = %v %s, want %v SESSION_NOT_FOUND", code, reason, codes.NotFound)
+	}
+
+	// Expired sessions no longer count against the limit.
+	s.sessions.cfg.MaxSessions = 2
+	create()
+	create()
+	_, err := s.CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+	if code, reason, _ := errorInfo(err); code != codes.ResourceExhausted || reason != "TOO_MANY_SESSIONS" {
+		t.Errorf("creating a session over the limit = %v %s, want %v TOO_MANY_SESSIONS", code, reason, codes.ResourceExhausted)
+	}
+	advance(ttl)
+	create()
+	s.sessions.mu.Lock()
+	n := len(s.sessions.sessions)
+	s.sessions.mu.Unlock()
+	if n != 1 {
+		t.Errorf("the store keeps %d sessions after the others expired, want 1", n)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// activeCall is an RPC that is currently being handled.
+type activeCall struct {
+	method string
+	peer   string
+	start  time.Time
+}
+
+// callTracker keeps track of in-flight RPCs so that a shutdown can report
+// which ones it had to cut off.
+type callTracker struct {
+	mu     sync.Mutex
+	nextID uint64
+	calls  map[uint64]activeCall
+}
+
+func newCallTracker() *callTracker {
+	return &callTracker{calls: map[uint64]activeCall{}}
+}
+
+// begin records a call and returns the function that marks it finished.
+func (t *callTracker) begin(ctx context.Context, method string) func() {
+	call := activeCall{method: method, start: time.Now()}
+	if p, ok := peer.FromContext(ctx); ok {
+		call.peer = p.Addr.String()
+	}
+
+	t.mu.Lock()
+	id := t.nextID
+	t.nextID++
+	t.calls[id] = call
+	t.mu.Unlock()
+
+	return func() {
+		t.mu.Lock()
+		delete(t.calls, id)
+		t.mu.Unlock()
+	}
+}
+
+// active returns the calls in flight, oldest first.
+func (t *callTracker) active() []activeCall {
+	t.mu.Lock()
+	calls := make([]activeCall, 0, len(t.calls))
+	for _, call := range t.calls {
+		calls = append(calls, call)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(calls, func(i, j int) bool { return calls[i].start.Before(calls[j].start) })
+	return calls
+}
+
+func (t *callTracker) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	defer t.begin(ctx, info.FullMethod)()
+	return handler(ctx, req)
+}
+
+func (t *callTracker) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	defer t.begin(ss.Context(), info.FullMethod)()
+	return handler(srv, ss)
+}
+
+// handlerExitTimeout is how long a forced shutdown waits for canceled
+// handlers to return.
+const handlerExitTimeout = 5 * time.Second
+
+// gracefulShutdown waits drain for load balancers to notice that the server
+// is no longer healthy, then stops accepting new RPCs and waits up to grace
+// for the active ones to finish. If they don't, or another signal arrives,
+// the server is stopped forcibly and the calls that were cut off are logged.
+func gracefulShutdown(s *grpc.Server, tracker *callTracker, drain, grace time.Duration, signals <-chan os.Signal) {
+	if drain > 0 {
+		slog.Info("Draining, still accepting calls while health checks report NOT_SERVING", "drain_delay", drain.String())
+		select {
+		case <-time.After(drain):
+		case sig := <-signals:
+			slog.Warn("Received another signal, ending the drain early", "signal", sig.String())
+		}
+	}
+
+	slog.Info("Shutting down, waiting for active calls to finish", "active_calls", len(tracker.active()), "grace_period", grace.String())
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		slog.Info("Server stopped, all calls finished")
+		return
+	case <-time.After(grace):
+		slog.Warn("Grace period expired, forcing shutdown")
+	case sig := <-signals:
+		slog.Warn("Received another signal, forcing shutdown", "signal", sig.String())
+	}
+
+	cutOff := tracker.active()
+	go s.Stop()
+
+	// Stop waits for handlers to return after canceling them; don't let one
+	// that ignores its context keep the process alive.
+	select {
+	case <-stopped:
+	case <-time.After(handlerExitTimeout):
+		slog.Warn("Some handlers did not return after being canceled")
+	}
+
+	for _, call := range cutOff {
+		slog.Warn("Call cut off by shutdown", "method", call.method, "peer", call.peer, "running_for", time.Since(call.start).Round(time.Millisecond).String())
+	}
+	slog.Warn("Server stopped", "cut_off_calls", len(cutOff))
+}
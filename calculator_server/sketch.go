@@ -0,0 +1,126 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// sketchRelativeError is the relative accuracy of quantileSketch.
+	sketchRelativeError = 0.01
+
+	// sketchMaxBuckets bounds the memory of a quantileSketch. Beyond it the
+	// buckets closest to zero are merged, which only costs accuracy for the
+	// values of smallest magnitude.
+	sketchMaxBuckets = 2048
+)
+
+// quantileSketch estimates the quantiles of a stream of numbers in bounded
+// memory, like DDSketch: values are counted in buckets on a logarithmic
+// scale, each holding values within sketchRelativeError of one estimate.
+type quantileSketch struct {
+	gamma    float64
+	logGamma float64
+	// positive and negative count the values by the bucket of their
+	// magnitude; bucket i holds the magnitudes in (gamma^(i-1), gamma^i].
+	positive map[int]uint64
+	negative map[int]uint64
+	// positiveFloor and negativeFloor are the lowest buckets left once
+	// collapse merged those below them; smaller magnitudes are counted in
+	// them, so that they do not make new buckets to merge again.
+	positiveFloor, negativeFloor int
+	zeros                        uint64
+	count                        uint64
+}
+
+func newQuantileSketch() *quantileSketch {
+	gamma := (1 + sketchRelativeError) / (1 - sketchRelativeError)
+	return &quantileSketch{
+		gamma:         gamma,
+		logGamma:      math.Log(gamma),
+		positive:      map[int]uint64{},
+		negative:      map[int]uint64{},
+		positiveFloor: math.MinInt,
+		negativeFloor: math.MinInt,
+	}
+}
+
+func (s *quantileSketch) add(x float64) {
+	s.count++
+	switch {
+	case x > 0:
+		s.positive[max(s.bucket(x), s.positiveFloor)]++
+	case x < 0:
+		s.negative[max(s.bucket(-x), s.negativeFloor)]++
+	default:
+		s.zeros++
+	}
+	if len(s.positive)+len(s.negative) > sketchMaxBuckets {
+		s.collapse()
+	}
+}
+
+func (s *quantileSketch) bucket(magnitude float64) int {
+	return int(math.Ceil(math.Log(magnitude) / s.logGamma))
+}
+
+// estimate is the magnitude that is within sketchRelativeError of every
+// magnitude in bucket i.
+func (s *quantileSketch) estimate(i int) float64 {
+	return 2 * math.Pow(s.gamma, float64(i)) / (s.gamma + 1)
+}
+
+// quantile estimates the q-quantile, 0 <= q <= 1, of the values added.
+func (s *quantileSketch) quantile(q float64) float64 {
+	rank := uint64(q * float64(s.count-1))
+	var seen uint64
+	negative := sortedBuckets(s.negative)
+	for j := len(negative) - 1; j >= 0; j-- {
+		seen += s.negative[negative[j]]
+		if seen > rank {
+			return -s.estimate(negative[j])
+		}
+	}
+	seen += s.zeros
+	if seen > rank {
+		return 0
+	}
+	positive := sortedBuckets(s.positive)
+	for _, i := range positive {
+		seen += s.positive[i]
+		if seen > rank {
+			return s.estimate(i)
+		}
+	}
+	return s.estimate(positive[len(positive)-1])
+}
+
+// collapse merges the buckets closest to zero until there are at most
+// sketchMaxBuckets.
+func (s *quantileSketch) collapse() {
+	for _, side := range []struct {
+		buckets map[int]uint64
+		floor   *int
+	}{{s.positive, &s.positiveFloor}, {s.negative, &s.negativeFloor}} {
+		excess := len(s.positive) + len(s.negative) - sketchMaxBuckets
+		if excess <= 0 || len(side.buckets) < 2 {
+			continue
+		}
+		keys := sortedBuckets(side.buckets)
+		n := min(excess, len(keys)-1)
+		for _, i := range keys[:n] {
+			side.buckets[keys[n]] += side.buckets[i]
+			delete(side.buckets, i)
+		}
+		*side.floor = keys[n]
+	}
+}
+
+func sortedBuckets(buckets map[int]uint64) []int {
+	keys := make([]int, 0, len(buckets))
+	for i := range buckets {
+		keys = append(keys, i)
+	}
+	sort.Ints(keys)
+	return keys
+}
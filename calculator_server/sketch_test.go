@@ -0,0 +1,80 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkQuantiles checks that the sketch of values estimates their quantiles
+// at the ranks ComputeStatistics uses within sketchRelativeError, leaving
+// out quantiles below minQuantile.
+func checkQuantiles(t *testing.T, name string, values []float64, minQuantile float64) {
+	t.Helper()
+	s := newQuantileSketch()
+	for _, x := range values {
+		s.add(x)
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	for _, q := range []float64{0, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99, 0.999, 1} {
+		if q < minQuantile {
+			continue
+		}
+		exact := sorted[uint64(q*float64(len(sorted)-1))]
+		// A little slack for rounding in the logarithms.
+		if got := s.quantile(q); math.Abs(got-exact) > sketchRelativeError*math.Abs(exact)*(1+1e-9) {
+			t.Errorf("%s: quantile(%v) = %v, want %v within %v", name, q, got, exact, sketchRelativeError)
+		}
+	}
+}
+
+func TestQuantileSketch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	generate := func(n int, f func() float64) []float64 {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = f()
+		}
+		return values
+	}
+
+	tests := []struct {
+		name   string
+		values []float64
+	}{
+		{"one value", []float64{42}},
+		{"constant", generate(1000, func() float64 { return -3.5 })},
+		{"integers", generate(100000, func() float64 { return float64(rng.Intn(1000) + 1) })},
+		{"uniform", generate(100000, func() float64 { return rng.Float64() * 1e6 })},
+		{"lognormal", generate(100000, func() float64 { return math.Exp(rng.NormFloat64() * 5) })},
+		{"signed", generate(100000, func() float64 { return rng.NormFloat64() * 1000 })},
+		{"with zeros", generate(100000, func() float64 { return float64(rng.Intn(3)-1) * rng.ExpFloat64() })},
+		{"tiny and huge", generate(10000, func() float64 { return math.Pow(10, float64(rng.Intn(200)-100)) })},
+	}
+	for _, tt := range tests {
+		checkQuantiles(t, tt.name, tt.values, 0)
+	}
+}
+
+func TestQuantileSketchCollapse(t *testing.T) {
+	// Values from 1e-20 to 1e20 need over twice sketchMaxBuckets; the
+	// buckets kept hold those above about 1e2.
+	rng := rand.New(rand.NewSource(1))
+	values := make([]float64, 100000)
+	for i := range values {
+		values[i] = math.Pow(10, rng.Float64()*40-20)
+	}
+
+	s := newQuantileSketch()
+	for _, x := range values {
+		s.add(x)
+	}
+	if n := len(s.positive) + len(s.negative); n > sketchMaxBuckets {
+		t.Errorf("the sketch has %d buckets, want at most %d", n, sketchMaxBuckets)
+	}
+	// Only the values closest to zero lose accuracy.
+	checkQuantiles(t, "collapsed", values, 0.6)
+}
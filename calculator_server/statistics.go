@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/ErFUN-KH/simple-grpc-project/calculatorpb"
+	"google.golang.org/grpc/codes"
+)
+
+// maxPercentiles bounds how many percentiles one ComputeStatistics call may
+// ask for.
+const maxPercentiles = 100
+
+func (s *server) ComputeStatistics(stream calculatorpb.CalculatorService_ComputeStatisticsServer) error {
+	ctx := stream.Context()
+	stats := newStreamStats(s.stats.ExactLimit)
+	var percentiles []float64
+
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return streamError(ctx, err, "reading client stream")
+		}
+
+		if len(req.GetPercentiles()) > 0 {
+			if !first {
+				return requestError{
+					code:        codes.InvalidArgument,
+					reason:      "LATE_PERCENTILES",
+					field:       "percentiles",
+					description: "may only be set in the first message",
+					message:     "Percentiles must be requested in the first message of the stream",
+				}.err()
+			}
+			if err := checkPercentiles(req.GetPercentiles()); err != nil {
+				return err
+			}
+			percentiles = req.GetPercentiles()
+		}
+		for _, x := range req.GetNumbers() {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return requestError{
+					code:        codes.InvalidArgument,
+					reason:      "INVALID_NUMBER",
+					field:       "numbers",
+					description: "must be finite",
+					message:     fmt.Sprintf("Cannot compute statistics of %v", x),
+				}.err()
+			}
+			stats.add(x)
+		}
+	}
+
+	if stats.count == 0 {
+		return requestError{
+			code:        codes.InvalidArgument,
+			reason:      "EMPTY_STREAM",
+			field:       "numbers",
+			description: "at least one number is required",
+			message:     "Cannot compute statistics of an empty stream of numbers",
+		}.err()
+	}
+	return stream.SendAndClose(stats.result(percentiles))
+}
+
+func checkPercentiles(percentiles []float64) error {
+	if len(percentiles) > maxPercentiles {
+		return requestError{
+			code:        codes.InvalidArgument,
+			reason:      "TOO_MANY_PERCENTILES",
+			field:       "percentiles",
+			description: fmt.Sprintf("at most %d are allowed", maxPercentiles),
+			message:     fmt.Sprintf("%d percentiles requested, at most %d are allowed", len(percentiles), maxPercentiles),
+			metadata:    map[string]string{"max_percentiles": strconv.Itoa(maxPercentiles)},
+		}.err()
+	}
+	for _, p := range percentiles {
+		if !(p >= 0 && p <= 100) {
+			return requestError{
+				code:        codes.InvalidArgument,
+				reason:      "INVALID_PERCENTILE",
+				field:       "percentiles",
+				description: "must be between 0 and 100",
+				message:     fmt.Sprintf("Invalid percentile %v: must be between 0 and 100", p),
+			}.err()
+		}
+	}
+	return nil
+}
+
+// streamStats accumulates the statistics of a stream of numbers in one pass.
+// It keeps the numbers for exact percentiles until there are more than
+// exactLimit, then moves them into a quantileSketch.
+type streamStats struct {
+	count    uint64
+	min, max float64
+	// sum and compensation are a Neumaier compensated sum.
+	sum, compensation float64
+	// mean and m2 are updated with Welford's algorithm; m2 is the sum of
+	// squared differences from the mean.
+	mean, m2 float64
+
+	exactLimit int
+	values     []float64
+	sketch     *quantileSketch
+}
+
+func newStreamStats(exactLimit int) *streamStats {
+	st := &streamStats{exactLimit: exactLimit}
+	if exactLimit == 0 {
+		st.sketch = newQuantileSketch()
+	}
+	return st
+}
+
+func (st *streamStats) add(x float64) {
+	st.count++
+	if st.count == 1 || x < st.min {
+		st.min = x
+	}
+	if st.count == 1 || x > st.max {
+		st.max = x
+	}
+
+	t := st.sum + x
+	if math.Abs(st.sum) >= math.Abs(x) {
+		st.compensation += (st.sum - t) + x
+	} else {
+		st.compensation += (x - t) + st.sum
+	}
+	st.sum = t
+
+	delta := x - st.mean
+	st.mean += delta / float64(st.count)
+	st.m2 += delta * (x - st.mean)
+
+	if st.sketch != nil {
+		st.sketch.add(x)
+		return
+	}
+	st.values = append(st.values, x)
+	if len(st.values) > st.exactLimit {
+		st.sketch = newQuantileSketch()
+		for _, v := range st.values {
+			st.sketch.add(v)
+		}
+		st.values = nil
+	}
+}
+
+func (st *streamStats) result(percentiles []float64) *calculatorpb.ComputeStatisticsResponse {
+	variance := st.m2 / float64(st.count)
+	res := &calculatorpb.ComputeStatisticsResponse{
+		Count:             st.count,
+		Sum:               st.sum + st.compensation,
+		Mean:              st.mean,
+		Min:               st.min,
+		Max:               st.max,
+		Variance:          variance,
+		StandardDeviation: math.Sqrt(variance),
+		Exact:             st.sketch == nil,
+	}
+	if !res.Exact {
+		res.RelativeError = sketchRelativeError
+	}
+	sort.Float64s(st.values)
+	res.Median = st.percentile(50)
+	for _, p := range percentiles {
+		res.Percentiles = append(res.Percentiles, &calculatorpb.Percentile{
+			Percentile: p,
+			Value:      st.percentile(p),
+		})
+	}
+	return res
+}
+
+// percentile returns the p-th percentile, interpolating linearly between the
+// closest ranks. st.values must be sorted.
+func (st *streamStats) percentile(p float64) float64 {
+	if st.sketch != nil {
+		// An estimate never lies outside the values of the stream.
+		return math.Max(st.min, math.Min(st.max, st.sketch.quantile(p/100)))
+	}
+	rank := p / 100 * float64(len(st.values)-1)
+	lo, hi := int(math.Floor(rank)), int(math.Ceil(rank))
+	return st.values[lo] + (st.values[hi]-st.values[lo])*(rank-float64(lo))
+}
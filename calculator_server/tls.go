@@ -0,0 +1,134 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// serverTLSConfig loads the server certificate and, when a client CA is
+// configured, requires every client to present a certificate signed by it.
+// The certificate is reloaded for new handshakes when its files change or the
+// process receives SIGHUP, depending on cfg.
+func serverTLSConfig(cfg tlsConfig) (*tls.Config, *certReloader, error) {
+	reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.WatchFiles {
+		if err := reloader.watch(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.ReloadOnSighup {
+		reloadOnSignal(reloader.reloadAndLog, syscall.SIGHUP)
+	}
+
+	tlsConfig := &tls.Config{
+		GetCertificate: reloader.getCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile != "" {
+		pool, err := loadCertPool(cfg.ClientCAFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, reloader, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("loading client CA: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("loading client CA: no certificates found in %s", file)
+	}
+	return pool, nil
+}
+
+// certReloader serves the most recently loaded certificate. Handshakes that
+// already happened keep the certificate they were made with, so open
+// connections and streams are unaffected by a reload.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload loads the certificate files again. On failure the previous
+// certificate stays in use.
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading server certificate: %v", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// checkValidity reports an error if the certificate in use has expired or is
+// not valid yet.
+func (r *certReloader) checkValidity() error {
+	r.mu.RLock()
+	cert := r.cert
+	r.mu.RUnlock()
+
+	leaf := cert.Leaf
+	if leaf == nil {
+		var err error
+		if leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return fmt.Errorf("certificate is only valid from %v to %v", leaf.NotBefore, leaf.NotAfter)
+	}
+	return nil
+}
+
+func (r *certReloader) reloadAndLog(reason string) {
+	if err := r.reload(); err != nil {
+		slog.Error("Failed to reload certificate, keeping the previous one", "reason", reason, "error", err)
+		return
+	}
+	slog.Info("Reloaded certificate", "reason", reason, "cert_file", r.certFile)
+}
+
+// watch reloads the certificate whenever one of its files changes.
+func (r *certReloader) watch() error {
+	if err := watchFiles([]string{r.certFile, r.keyFile}, r.reloadAndLog); err != nil {
+		return fmt.Errorf("watching certificate files: %v", err)
+	}
+	return nil
+}
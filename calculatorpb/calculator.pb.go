@@ -24,12 +24,204 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// OverflowMode selects what happens when a sum does not fit in an int32.
+type OverflowMode int32
+
+const (
+	// The call fails with OUT_OF_RANGE.
+	OverflowMode_OVERFLOW_MODE_CHECKED OverflowMode = 0
+	// The sum is clamped to the int32 range.
+	OverflowMode_OVERFLOW_MODE_SATURATING OverflowMode = 1
+	// The sum wraps around in two's complement.
+	OverflowMode_OVERFLOW_MODE_WRAPPING OverflowMode = 2
+	// The exact sum is returned in wide_sum_result instead of sum_result.
+	OverflowMode_OVERFLOW_MODE_WIDENED OverflowMode = 3
+)
+
+var OverflowMode_name = map[int32]string{
+	0: "OVERFLOW_MODE_CHECKED",
+	1: "OVERFLOW_MODE_SATURATING",
+	2: "OVERFLOW_MODE_WRAPPING",
+	3: "OVERFLOW_MODE_WIDENED",
+}
+
+var OverflowMode_value = map[string]int32{
+	"OVERFLOW_MODE_CHECKED":    0,
+	"OVERFLOW_MODE_SATURATING": 1,
+	"OVERFLOW_MODE_WRAPPING":   2,
+	"OVERFLOW_MODE_WIDENED":    3,
+}
+
+func (x OverflowMode) String() string {
+	return proto.EnumName(OverflowMode_name, int32(x))
+}
+
+func (OverflowMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{0}
+}
+
+// BigOperation is an operation on arbitrary-precision numbers.
+type BigOperation int32
+
+const (
+	BigOperation_BIG_OPERATION_UNSPECIFIED BigOperation = 0
+	BigOperation_BIG_OPERATION_ADD         BigOperation = 1
+	BigOperation_BIG_OPERATION_SUBTRACT    BigOperation = 2
+	BigOperation_BIG_OPERATION_MULTIPLY    BigOperation = 3
+	// Integers: quotient truncated toward zero. Rationals: exact quotient.
+	BigOperation_BIG_OPERATION_DIVIDE BigOperation = 4
+	// Integers only: remainder of the truncated division, with the sign of a.
+	BigOperation_BIG_OPERATION_MODULO BigOperation = 5
+	// a to the power b. b must be an integer; it may be negative for
+	// rationals.
+	BigOperation_BIG_OPERATION_POWER BigOperation = 6
+	// Integers only: a to the power b modulo modulus. A negative b uses the
+	// modular inverse of a.
+	BigOperation_BIG_OPERATION_MOD_POW BigOperation = 7
+)
+
+var BigOperation_name = map[int32]string{
+	0: "BIG_OPERATION_UNSPECIFIED",
+	1: "BIG_OPERATION_ADD",
+	2: "BIG_OPERATION_SUBTRACT",
+	3: "BIG_OPERATION_MULTIPLY",
+	4: "BIG_OPERATION_DIVIDE",
+	5: "BIG_OPERATION_MODULO",
+	6: "BIG_OPERATION_POWER",
+	7: "BIG_OPERATION_MOD_POW",
+}
+
+var BigOperation_value = map[string]int32{
+	"BIG_OPERATION_UNSPECIFIED": 0,
+	"BIG_OPERATION_ADD":         1,
+	"BIG_OPERATION_SUBTRACT":    2,
+	"BIG_OPERATION_MULTIPLY":    3,
+	"BIG_OPERATION_DIVIDE":      4,
+	"BIG_OPERATION_MODULO":      5,
+	"BIG_OPERATION_POWER":       6,
+	"BIG_OPERATION_MOD_POW":     7,
+}
+
+func (x BigOperation) String() string {
+	return proto.EnumName(BigOperation_name, int32(x))
+}
+
+func (BigOperation) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{1}
+}
+
+// Aggregate is a value RunningAggregate computes over a window of numbers.
+type Aggregate int32
+
+const (
+	Aggregate_AGGREGATE_UNSPECIFIED Aggregate = 0
+	Aggregate_AGGREGATE_COUNT       Aggregate = 1
+	Aggregate_AGGREGATE_SUM         Aggregate = 2
+	Aggregate_AGGREGATE_MEAN        Aggregate = 3
+	Aggregate_AGGREGATE_MIN         Aggregate = 4
+	Aggregate_AGGREGATE_MAX         Aggregate = 5
+	// Exponentially weighted moving average with smoothing factor
+	// ewma_alpha. It starts at the first number of a tumbling window or of
+	// the stream; a sliding window does not bound it.
+	Aggregate_AGGREGATE_EWMA Aggregate = 6
+)
+
+var Aggregate_name = map[int32]string{
+	0: "AGGREGATE_UNSPECIFIED",
+	1: "AGGREGATE_COUNT",
+	2: "AGGREGATE_SUM",
+	3: "AGGREGATE_MEAN",
+	4: "AGGREGATE_MIN",
+	5: "AGGREGATE_MAX",
+	6: "AGGREGATE_EWMA",
+}
+
+var Aggregate_value = map[string]int32{
+	"AGGREGATE_UNSPECIFIED": 0,
+	"AGGREGATE_COUNT":       1,
+	"AGGREGATE_SUM":         2,
+	"AGGREGATE_MEAN":        3,
+	"AGGREGATE_MIN":         4,
+	"AGGREGATE_MAX":         5,
+	"AGGREGATE_EWMA":        6,
+}
+
+func (x Aggregate) String() string {
+	return proto.EnumName(Aggregate_name, int32(x))
+}
+
+func (Aggregate) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{2}
+}
+
+// WindowType selects which numbers RunningAggregate aggregates.
+type WindowType int32
+
+const (
+	// Every number of the stream.
+	WindowType_WINDOW_TYPE_CUMULATIVE WindowType = 0
+	// The last window_size numbers.
+	WindowType_WINDOW_TYPE_SLIDING_COUNT WindowType = 1
+	// The numbers received in consecutive windows of window_ms
+	// milliseconds, counted from the first message.
+	WindowType_WINDOW_TYPE_TUMBLING_TIME WindowType = 2
+)
+
+var WindowType_name = map[int32]string{
+	0: "WINDOW_TYPE_CUMULATIVE",
+	1: "WINDOW_TYPE_SLIDING_COUNT",
+	2: "WINDOW_TYPE_TUMBLING_TIME",
+}
+
+var WindowType_value = map[string]int32{
+	"WINDOW_TYPE_CUMULATIVE":    0,
+	"WINDOW_TYPE_SLIDING_COUNT": 1,
+	"WINDOW_TYPE_TUMBLING_TIME": 2,
+}
+
+func (x WindowType) String() string {
+	return proto.EnumName(WindowType_name, int32(x))
+}
+
+func (WindowType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{3}
+}
+
+// EmitMode selects when RunningAggregate sends an update.
+type EmitMode int32
+
+const (
+	// After every message with numbers.
+	EmitMode_EMIT_MODE_EVERY_MESSAGE EmitMode = 0
+	// After a message with numbers that changed a value.
+	EmitMode_EMIT_MODE_ON_CHANGE EmitMode = 1
+)
+
+var EmitMode_name = map[int32]string{
+	0: "EMIT_MODE_EVERY_MESSAGE",
+	1: "EMIT_MODE_ON_CHANGE",
+}
+
+var EmitMode_value = map[string]int32{
+	"EMIT_MODE_EVERY_MESSAGE": 0,
+	"EMIT_MODE_ON_CHANGE":     1,
+}
+
+func (x EmitMode) String() string {
+	return proto.EnumName(EmitMode_name, int32(x))
+}
+
+func (EmitMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{4}
+}
+
 type SumRequest struct {
-	FirstNumber          int32    `protobuf:"varint,1,opt,name=first_number,json=firstNumber,proto3" json:"first_number,omitempty"`
-	SecondUmber          int32    `protobuf:"varint,2,opt,name=second_umber,json=secondUmber,proto3" json:"second_umber,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	FirstNumber          int32        `protobuf:"varint,1,opt,name=first_number,json=firstNumber,proto3" json:"first_number,omitempty"`
+	SecondUmber          int32        `protobuf:"varint,2,opt,name=second_umber,json=secondUmber,proto3" json:"second_umber,omitempty"`
+	OverflowMode         OverflowMode `protobuf:"varint,3,opt,name=overflow_mode,json=overflowMode,proto3,enum=calculator.OverflowMode" json:"overflow_mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *SumRequest) Reset()         { *m = SumRequest{} }
@@ -71,8 +263,19 @@ func (m *SumRequest) GetSecondUmber() int32 {
 	return 0
 }
 
+func (m *SumRequest) GetOverflowMode() OverflowMode {
+	if m != nil {
+		return m.OverflowMode
+	}
+	return OverflowMode_OVERFLOW_MODE_CHECKED
+}
+
 type SumResponse struct {
-	SumResult            int32    `protobuf:"varint,1,opt,name=sum_result,json=sumResult,proto3" json:"sum_result,omitempty"`
+	SumResult int32 `protobuf:"varint,1,opt,name=sum_result,json=sumResult,proto3" json:"sum_result,omitempty"`
+	// Set in OVERFLOW_MODE_WIDENED.
+	WideSumResult int64 `protobuf:"varint,2,opt,name=wide_sum_result,json=wideSumResult,proto3" json:"wide_sum_result,omitempty"`
+	// True if sum_result was saturated or wrapped.
+	Overflowed           bool     `protobuf:"varint,3,opt,name=overflowed,proto3" json:"overflowed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -110,6 +313,20 @@ func (m *SumResponse) GetSumResult() int32 {
 	return 0
 }
 
+func (m *SumResponse) GetWideSumResult() int64 {
+	if m != nil {
+		return m.WideSumResult
+	}
+	return 0
+}
+
+func (m *SumResponse) GetOverflowed() bool {
+	if m != nil {
+		return m.Overflowed
+	}
+	return false
+}
+
 type PrimeNumberDecompositionRequest struct {
 	Number               int64    `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -423,11 +640,12 @@ func (m *SquareRootResponse) GetNumberRoot() float64 {
 }
 
 type SumWithDeadLineRequest struct {
-	FirstNumber          int32    `protobuf:"varint,1,opt,name=first_number,json=firstNumber,proto3" json:"first_number,omitempty"`
-	SecondUmber          int32    `protobuf:"varint,2,opt,name=second_umber,json=secondUmber,proto3" json:"second_umber,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	FirstNumber          int32        `protobuf:"varint,1,opt,name=first_number,json=firstNumber,proto3" json:"first_number,omitempty"`
+	SecondUmber          int32        `protobuf:"varint,2,opt,name=second_umber,json=secondUmber,proto3" json:"second_umber,omitempty"`
+	OverflowMode         OverflowMode `protobuf:"varint,3,opt,name=overflow_mode,json=overflowMode,proto3,enum=calculator.OverflowMode" json:"overflow_mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *SumWithDeadLineRequest) Reset()         { *m = SumWithDeadLineRequest{} }
@@ -469,8 +687,19 @@ func (m *SumWithDeadLineRequest) GetSecondUmber() int32 {
 	return 0
 }
 
+func (m *SumWithDeadLineRequest) GetOverflowMode() OverflowMode {
+	if m != nil {
+		return m.OverflowMode
+	}
+	return OverflowMode_OVERFLOW_MODE_CHECKED
+}
+
 type SumWithDeadLineResponse struct {
-	SumResult            int32    `protobuf:"varint,1,opt,name=sum_result,json=sumResult,proto3" json:"sum_result,omitempty"`
+	SumResult int32 `protobuf:"varint,1,opt,name=sum_result,json=sumResult,proto3" json:"sum_result,omitempty"`
+	// Set in OVERFLOW_MODE_WIDENED.
+	WideSumResult int64 `protobuf:"varint,2,opt,name=wide_sum_result,json=wideSumResult,proto3" json:"wide_sum_result,omitempty"`
+	// True if sum_result was saturated or wrapped.
+	Overflowed           bool     `protobuf:"varint,3,opt,name=overflowed,proto3" json:"overflowed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -508,208 +737,2250 @@ func (m *SumWithDeadLineResponse) GetSumResult() int32 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*SumRequest)(nil), "calculator.SumRequest")
-	proto.RegisterType((*SumResponse)(nil), "calculator.SumResponse")
-	proto.RegisterType((*PrimeNumberDecompositionRequest)(nil), "calculator.PrimeNumberDecompositionRequest")
-	proto.RegisterType((*PrimeNumberDecompositionResponse)(nil), "calculator.PrimeNumberDecompositionResponse")
-	proto.RegisterType((*ComputeAverageRequest)(nil), "calculator.ComputeAverageRequest")
-	proto.RegisterType((*ComputeAverageResponse)(nil), "calculator.ComputeAverageResponse")
-	proto.RegisterType((*FindMaximumRequest)(nil), "calculator.FindMaximumRequest")
-	proto.RegisterType((*FindMaximumResponse)(nil), "calculator.FindMaximumResponse")
-	proto.RegisterType((*SquareRootRequest)(nil), "calculator.SquareRootRequest")
-	proto.RegisterType((*SquareRootResponse)(nil), "calculator.SquareRootResponse")
-	proto.RegisterType((*SumWithDeadLineRequest)(nil), "calculator.SumWithDeadLineRequest")
-	proto.RegisterType((*SumWithDeadLineResponse)(nil), "calculator.SumWithDeadLineResponse")
+func (m *SumWithDeadLineResponse) GetWideSumResult() int64 {
+	if m != nil {
+		return m.WideSumResult
+	}
+	return 0
 }
 
-func init() { proto.RegisterFile("calculatorpb/calculator.proto", fileDescriptor_87e717c78a24322a) }
-
-var fileDescriptor_87e717c78a24322a = []byte{
-	// 478 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x94, 0x5f, 0x6f, 0xd3, 0x30,
-	0x14, 0xc5, 0x09, 0x63, 0x43, 0xdc, 0x54, 0x43, 0x33, 0xa2, 0xab, 0x22, 0x75, 0x5d, 0xcd, 0xcb,
-	0xa4, 0x55, 0xeb, 0x34, 0x84, 0x34, 0x1e, 0x61, 0x63, 0x4f, 0x0c, 0xa1, 0x04, 0x84, 0x04, 0x88,
-	0x28, 0x4d, 0xef, 0xc0, 0x52, 0x1d, 0x67, 0xfe, 0x33, 0xc1, 0x37, 0xe2, 0x63, 0xa2, 0xc6, 0x49,
-	0xe3, 0xa6, 0xcd, 0xb6, 0x07, 0x1e, 0xef, 0xf1, 0xb9, 0xe7, 0x58, 0xee, 0xaf, 0x81, 0x7e, 0x9a,
-	0xcc, 0x52, 0x33, 0x4b, 0xb4, 0x90, 0xf9, 0x64, 0x5c, 0x0f, 0x47, 0xb9, 0x14, 0x5a, 0x10, 0xa8,
-	0x15, 0x1a, 0x02, 0x44, 0x86, 0x87, 0x78, 0x6d, 0x50, 0x69, 0x32, 0x84, 0xce, 0x15, 0x93, 0x4a,
-	0xc7, 0x99, 0xe1, 0x13, 0x94, 0x3d, 0x6f, 0xdf, 0x3b, 0xd8, 0x0c, 0xfd, 0x42, 0xfb, 0x50, 0x48,
-	0x73, 0x8b, 0xc2, 0x54, 0x64, 0xd3, 0xd8, 0x5a, 0x1e, 0x5a, 0x8b, 0xd5, 0x3e, 0xcf, 0x25, 0x3a,
-	0x02, 0xbf, 0xc8, 0x54, 0xb9, 0xc8, 0x14, 0x92, 0x3e, 0x80, 0x32, 0x3c, 0x96, 0xa8, 0xcc, 0x4c,
-	0x97, 0x91, 0x4f, 0x54, 0x61, 0x30, 0x33, 0x4d, 0x5f, 0xc3, 0xe0, 0xa3, 0x64, 0x1c, 0x6d, 0xfe,
-	0x39, 0xa6, 0x82, 0xe7, 0x42, 0x31, 0xcd, 0x44, 0x56, 0x5d, 0xab, 0x0b, 0x5b, 0xce, 0x85, 0x36,
-	0xc2, 0x72, 0xa2, 0xef, 0x60, 0xbf, 0x7d, 0xb5, 0x6c, 0x1f, 0x42, 0x27, 0x9f, 0x7b, 0xe2, 0xab,
-	0x24, 0xd5, 0xa2, 0x4a, 0xf0, 0x0b, 0xed, 0xa2, 0x90, 0xe8, 0x18, 0x9e, 0x9f, 0x09, 0x9e, 0x1b,
-	0x8d, 0x6f, 0x6e, 0x50, 0x26, 0x3f, 0x71, 0x7d, 0xef, 0xe6, 0xa2, 0xf7, 0x04, 0xba, 0xcd, 0x85,
-	0xb2, 0xad, 0x07, 0x8f, 0x13, 0x2b, 0x15, 0x2b, 0x5e, 0x58, 0x8d, 0x74, 0x04, 0xe4, 0x82, 0x65,
-	0xd3, 0xcb, 0xe4, 0x37, 0xe3, 0xf5, 0x83, 0xb7, 0x35, 0x8c, 0xe1, 0xd9, 0x92, 0xbb, 0x8e, 0xe7,
-	0x56, 0x2a, 0xfd, 0xd5, 0x48, 0x0f, 0x61, 0x27, 0xba, 0x36, 0x89, 0xc4, 0x50, 0x08, 0x7d, 0x57,
-	0xfa, 0x2b, 0x20, 0xae, 0xb9, 0x0c, 0x1f, 0x80, 0x6f, 0xcf, 0x63, 0x29, 0x84, 0x2e, 0xef, 0x0f,
-	0x56, 0x9a, 0x1b, 0xe9, 0x0f, 0xe8, 0x46, 0x86, 0x7f, 0x61, 0xfa, 0xd7, 0x39, 0x26, 0xd3, 0xf7,
-	0x2c, 0xc3, 0xff, 0xcb, 0xcd, 0x29, 0xec, 0xae, 0xe4, 0xdf, 0x8b, 0xa1, 0x93, 0xbf, 0x8f, 0x60,
-	0xe7, 0x6c, 0x01, 0x75, 0x84, 0xf2, 0x86, 0xa5, 0x48, 0x4e, 0x61, 0x23, 0x32, 0x9c, 0x74, 0x8f,
-	0x9c, 0x7f, 0x40, 0x0d, 0x7b, 0xb0, 0xbb, 0xa2, 0xdb, 0x32, 0xfa, 0x80, 0xfc, 0x81, 0x5e, 0x1b,
-	0x58, 0xe4, 0xd0, 0x5d, 0xbb, 0x83, 0xdc, 0x60, 0x74, 0x3f, 0x73, 0x55, 0x7c, 0xec, 0x91, 0x6f,
-	0xb0, 0xbd, 0xcc, 0x16, 0x19, 0xba, 0x19, 0x6b, 0x41, 0x0d, 0xe8, 0x6d, 0x96, 0x2a, 0xfc, 0xc0,
-	0x23, 0x9f, 0xc0, 0x77, 0xb0, 0x22, 0x7b, 0xee, 0xda, 0x2a, 0x9d, 0xc1, 0xa0, 0xf5, 0xbc, 0xce,
-	0x3c, 0xf6, 0xc8, 0x25, 0x40, 0x8d, 0x13, 0xe9, 0x2f, 0x3d, 0x6b, 0x93, 0xc9, 0x60, 0xaf, 0xed,
-	0x78, 0xf1, 0xf8, 0xdf, 0xe1, 0x69, 0x03, 0x03, 0x42, 0x1b, 0x3f, 0xd5, 0x1a, 0x06, 0x83, 0x17,
-	0xb7, 0x7a, 0xaa, 0xf4, 0xb7, 0xdb, 0x5f, 0x3b, 0xee, 0xd7, 0x71, 0xb2, 0x55, 0x7c, 0x13, 0x5f,
-	0xfe, 0x0b, 0x00, 0x00, 0xff, 0xff, 0x84, 0xb0, 0xa9, 0x5d, 0x34, 0x05, 0x00, 0x00,
+func (m *SumWithDeadLineResponse) GetOverflowed() bool {
+	if m != nil {
+		return m.Overflowed
+	}
+	return false
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// CalculatorServiceClient is the client API for CalculatorService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type CalculatorServiceClient interface {
-	// Unary
-	Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error)
-	// Server Streaming
-	PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalculatorService_PrimeNumberDecompositionClient, error)
-	// Client Streaming
-	ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeAverageClient, error)
-	// BiDi Streaming
-	FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaximumClient, error)
-	// Error Handing
-	SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
-	// Dead Line
-	SumWithDeadLine(ctx context.Context, in *SumWithDeadLineRequest, opts ...grpc.CallOption) (*SumWithDeadLineResponse, error)
+type BigIntRequest struct {
+	Operation BigOperation `protobuf:"varint,1,opt,name=operation,proto3,enum=calculator.BigOperation" json:"operation,omitempty"`
+	// Decimal integers, e.g. "-123456789012345678901234567890".
+	A string `protobuf:"bytes,2,opt,name=a,proto3" json:"a,omitempty"`
+	B string `protobuf:"bytes,3,opt,name=b,proto3" json:"b,omitempty"`
+	// Positive modulus for BIG_OPERATION_MOD_POW.
+	Modulus              string   `protobuf:"bytes,4,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type calculatorServiceClient struct {
-	cc *grpc.ClientConn
+func (m *BigIntRequest) Reset()         { *m = BigIntRequest{} }
+func (m *BigIntRequest) String() string { return proto.CompactTextString(m) }
+func (*BigIntRequest) ProtoMessage()    {}
+func (*BigIntRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{12}
 }
 
-func NewCalculatorServiceClient(cc *grpc.ClientConn) CalculatorServiceClient {
-	return &calculatorServiceClient{cc}
+func (m *BigIntRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BigIntRequest.Unmarshal(m, b)
+}
+func (m *BigIntRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BigIntRequest.Marshal(b, m, deterministic)
+}
+func (m *BigIntRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BigIntRequest.Merge(m, src)
+}
+func (m *BigIntRequest) XXX_Size() int {
+	return xxx_messageInfo_BigIntRequest.Size(m)
+}
+func (m *BigIntRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BigIntRequest.DiscardUnknown(m)
 }
 
-func (c *calculatorServiceClient) Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error) {
-	out := new(SumResponse)
-	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Sum", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_BigIntRequest proto.InternalMessageInfo
+
+func (m *BigIntRequest) GetOperation() BigOperation {
+	if m != nil {
+		return m.Operation
 	}
-	return out, nil
+	return BigOperation_BIG_OPERATION_UNSPECIFIED
 }
 
-func (c *calculatorServiceClient) PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalculatorService_PrimeNumberDecompositionClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[0], "/calculator.CalculatorService/PrimeNumberDecomposition", opts...)
-	if err != nil {
-		return nil, err
+func (m *BigIntRequest) GetA() string {
+	if m != nil {
+		return m.A
 	}
-	x := &calculatorServicePrimeNumberDecompositionClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	return ""
+}
+
+func (m *BigIntRequest) GetB() string {
+	if m != nil {
+		return m.B
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	return ""
+}
+
+func (m *BigIntRequest) GetModulus() string {
+	if m != nil {
+		return m.Modulus
 	}
-	return x, nil
+	return ""
 }
 
-type CalculatorService_PrimeNumberDecompositionClient interface {
-	Recv() (*PrimeNumberDecompositionResponse, error)
-	grpc.ClientStream
+type BigIntResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type calculatorServicePrimeNumberDecompositionClient struct {
-	grpc.ClientStream
+func (m *BigIntResponse) Reset()         { *m = BigIntResponse{} }
+func (m *BigIntResponse) String() string { return proto.CompactTextString(m) }
+func (*BigIntResponse) ProtoMessage()    {}
+func (*BigIntResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{13}
 }
 
-func (x *calculatorServicePrimeNumberDecompositionClient) Recv() (*PrimeNumberDecompositionResponse, error) {
-	m := new(PrimeNumberDecompositionResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (m *BigIntResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BigIntResponse.Unmarshal(m, b)
+}
+func (m *BigIntResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BigIntResponse.Marshal(b, m, deterministic)
+}
+func (m *BigIntResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BigIntResponse.Merge(m, src)
+}
+func (m *BigIntResponse) XXX_Size() int {
+	return xxx_messageInfo_BigIntResponse.Size(m)
+}
+func (m *BigIntResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BigIntResponse.DiscardUnknown(m)
 }
 
-func (c *calculatorServiceClient) ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeAverageClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[1], "/calculator.CalculatorService/ComputeAverage", opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_BigIntResponse proto.InternalMessageInfo
+
+func (m *BigIntResponse) GetResult() string {
+	if m != nil {
+		return m.Result
 	}
-	x := &calculatorServiceComputeAverageClient{stream}
-	return x, nil
+	return ""
 }
 
-type CalculatorService_ComputeAverageClient interface {
-	Send(*ComputeAverageRequest) error
-	CloseAndRecv() (*ComputeAverageResponse, error)
-	grpc.ClientStream
+type BigRatRequest struct {
+	Operation BigOperation `protobuf:"varint,1,opt,name=operation,proto3,enum=calculator.BigOperation" json:"operation,omitempty"`
+	// Fractions such as "-3/4" or decimals such as "1.25".
+	A string `protobuf:"bytes,2,opt,name=a,proto3" json:"a,omitempty"`
+	B string `protobuf:"bytes,3,opt,name=b,proto3" json:"b,omitempty"`
+	// If set, the result is also returned as a decimal rounded to this many
+	// places.
+	DecimalPlaces        uint32   `protobuf:"varint,4,opt,name=decimal_places,json=decimalPlaces,proto3" json:"decimal_places,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type calculatorServiceComputeAverageClient struct {
-	grpc.ClientStream
+func (m *BigRatRequest) Reset()         { *m = BigRatRequest{} }
+func (m *BigRatRequest) String() string { return proto.CompactTextString(m) }
+func (*BigRatRequest) ProtoMessage()    {}
+func (*BigRatRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{14}
 }
 
-func (x *calculatorServiceComputeAverageClient) Send(m *ComputeAverageRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *BigRatRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BigRatRequest.Unmarshal(m, b)
+}
+func (m *BigRatRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BigRatRequest.Marshal(b, m, deterministic)
+}
+func (m *BigRatRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BigRatRequest.Merge(m, src)
+}
+func (m *BigRatRequest) XXX_Size() int {
+	return xxx_messageInfo_BigRatRequest.Size(m)
+}
+func (m *BigRatRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BigRatRequest.DiscardUnknown(m)
 }
 
-func (x *calculatorServiceComputeAverageClient) CloseAndRecv() (*ComputeAverageResponse, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+var xxx_messageInfo_BigRatRequest proto.InternalMessageInfo
+
+func (m *BigRatRequest) GetOperation() BigOperation {
+	if m != nil {
+		return m.Operation
 	}
-	m := new(ComputeAverageResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	return BigOperation_BIG_OPERATION_UNSPECIFIED
+}
+
+func (m *BigRatRequest) GetA() string {
+	if m != nil {
+		return m.A
 	}
-	return m, nil
+	return ""
 }
 
-func (c *calculatorServiceClient) FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaximumClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[2], "/calculator.CalculatorService/FindMaximum", opts...)
-	if err != nil {
-		return nil, err
+func (m *BigRatRequest) GetB() string {
+	if m != nil {
+		return m.B
 	}
-	x := &calculatorServiceFindMaximumClient{stream}
-	return x, nil
+	return ""
 }
 
-type CalculatorService_FindMaximumClient interface {
-	Send(*FindMaximumRequest) error
-	Recv() (*FindMaximumResponse, error)
-	grpc.ClientStream
+func (m *BigRatRequest) GetDecimalPlaces() uint32 {
+	if m != nil {
+		return m.DecimalPlaces
+	}
+	return 0
 }
 
-type calculatorServiceFindMaximumClient struct {
-	grpc.ClientStream
+type BigRatResponse struct {
+	// The result in lowest terms, e.g. "5/4", or an integer such as "2".
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Decimal              string   `protobuf:"bytes,2,opt,name=decimal,proto3" json:"decimal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (x *calculatorServiceFindMaximumClient) Send(m *FindMaximumRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *BigRatResponse) Reset()         { *m = BigRatResponse{} }
+func (m *BigRatResponse) String() string { return proto.CompactTextString(m) }
+func (*BigRatResponse) ProtoMessage()    {}
+func (*BigRatResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{15}
 }
 
-func (x *calculatorServiceFindMaximumClient) Recv() (*FindMaximumResponse, error) {
-	m := new(FindMaximumResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *BigRatResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BigRatResponse.Unmarshal(m, b)
+}
+func (m *BigRatResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BigRatResponse.Marshal(b, m, deterministic)
+}
+func (m *BigRatResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BigRatResponse.Merge(m, src)
+}
+func (m *BigRatResponse) XXX_Size() int {
+	return xxx_messageInfo_BigRatResponse.Size(m)
+}
+func (m *BigRatResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BigRatResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BigRatResponse proto.InternalMessageInfo
+
+func (m *BigRatResponse) GetResult() string {
+	if m != nil {
+		return m.Result
 	}
-	return m, nil
+	return ""
 }
 
-func (c *calculatorServiceClient) SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
-	out := new(SquareRootResponse)
-	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SquareRoot", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *BigRatResponse) GetDecimal() string {
+	if m != nil {
+		return m.Decimal
 	}
-	return out, nil
+	return ""
 }
 
-func (c *calculatorServiceClient) SumWithDeadLine(ctx context.Context, in *SumWithDeadLineRequest, opts ...grpc.CallOption) (*SumWithDeadLineResponse, error) {
-	out := new(SumWithDeadLineResponse)
+type FactorizeRequest struct {
+	// A positive decimal integer of any size, e.g. "600851475143".
+	Number string `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	// Work budget in milliseconds, if lower than the server's.
+	BudgetMs             uint32   `protobuf:"varint,2,opt,name=budget_ms,json=budgetMs,proto3" json:"budget_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FactorizeRequest) Reset()         { *m = FactorizeRequest{} }
+func (m *FactorizeRequest) String() string { return proto.CompactTextString(m) }
+func (*FactorizeRequest) ProtoMessage()    {}
+func (*FactorizeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{16}
+}
+
+func (m *FactorizeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FactorizeRequest.Unmarshal(m, b)
+}
+func (m *FactorizeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FactorizeRequest.Marshal(b, m, deterministic)
+}
+func (m *FactorizeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FactorizeRequest.Merge(m, src)
+}
+func (m *FactorizeRequest) XXX_Size() int {
+	return xxx_messageInfo_FactorizeRequest.Size(m)
+}
+func (m *FactorizeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FactorizeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FactorizeRequest proto.InternalMessageInfo
+
+func (m *FactorizeRequest) GetNumber() string {
+	if m != nil {
+		return m.Number
+	}
+	return ""
+}
+
+func (m *FactorizeRequest) GetBudgetMs() uint32 {
+	if m != nil {
+		return m.BudgetMs
+	}
+	return 0
+}
+
+// FactorPower is a factor and how many times it divides the number.
+type FactorPower struct {
+	Factor               string   `protobuf:"bytes,1,opt,name=factor,proto3" json:"factor,omitempty"`
+	Exponent             uint32   `protobuf:"varint,2,opt,name=exponent,proto3" json:"exponent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FactorPower) Reset()         { *m = FactorPower{} }
+func (m *FactorPower) String() string { return proto.CompactTextString(m) }
+func (*FactorPower) ProtoMessage()    {}
+func (*FactorPower) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{17}
+}
+
+func (m *FactorPower) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FactorPower.Unmarshal(m, b)
+}
+func (m *FactorPower) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FactorPower.Marshal(b, m, deterministic)
+}
+func (m *FactorPower) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FactorPower.Merge(m, src)
+}
+func (m *FactorPower) XXX_Size() int {
+	return xxx_messageInfo_FactorPower.Size(m)
+}
+func (m *FactorPower) XXX_DiscardUnknown() {
+	xxx_messageInfo_FactorPower.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FactorPower proto.InternalMessageInfo
+
+func (m *FactorPower) GetFactor() string {
+	if m != nil {
+		return m.Factor
+	}
+	return ""
+}
+
+func (m *FactorPower) GetExponent() uint32 {
+	if m != nil {
+		return m.Exponent
+	}
+	return 0
+}
+
+type FactorizeResponse struct {
+	// A prime factor that was found. Not set on progress and final messages.
+	Prime *FactorPower `protobuf:"bytes,1,opt,name=prime,proto3" json:"prime,omitempty"`
+	// Progress: work done so far, and the size of the part of the number
+	// that is not factored yet.
+	Steps           uint64 `protobuf:"varint,2,opt,name=steps,proto3" json:"steps,omitempty"`
+	RemainingDigits uint32 `protobuf:"varint,3,opt,name=remaining_digits,json=remainingDigits,proto3" json:"remaining_digits,omitempty"`
+	// Set on the final message.
+	Done bool `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`
+	// Set on the final message if the work budget ran out. unfactored holds
+	// the parts of the number that were not broken down into primes.
+	Incomplete           bool           `protobuf:"varint,5,opt,name=incomplete,proto3" json:"incomplete,omitempty"`
+	Unfactored           []*FactorPower `protobuf:"bytes,6,rep,name=unfactored,proto3" json:"unfactored,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *FactorizeResponse) Reset()         { *m = FactorizeResponse{} }
+func (m *FactorizeResponse) String() string { return proto.CompactTextString(m) }
+func (*FactorizeResponse) ProtoMessage()    {}
+func (*FactorizeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{18}
+}
+
+func (m *FactorizeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FactorizeResponse.Unmarshal(m, b)
+}
+func (m *FactorizeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FactorizeResponse.Marshal(b, m, deterministic)
+}
+func (m *FactorizeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FactorizeResponse.Merge(m, src)
+}
+func (m *FactorizeResponse) XXX_Size() int {
+	return xxx_messageInfo_FactorizeResponse.Size(m)
+}
+func (m *FactorizeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FactorizeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FactorizeResponse proto.InternalMessageInfo
+
+func (m *FactorizeResponse) GetPrime() *FactorPower {
+	if m != nil {
+		return m.Prime
+	}
+	return nil
+}
+
+func (m *FactorizeResponse) GetSteps() uint64 {
+	if m != nil {
+		return m.Steps
+	}
+	return 0
+}
+
+func (m *FactorizeResponse) GetRemainingDigits() uint32 {
+	if m != nil {
+		return m.RemainingDigits
+	}
+	return 0
+}
+
+func (m *FactorizeResponse) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+func (m *FactorizeResponse) GetIncomplete() bool {
+	if m != nil {
+		return m.Incomplete
+	}
+	return false
+}
+
+func (m *FactorizeResponse) GetUnfactored() []*FactorPower {
+	if m != nil {
+		return m.Unfactored
+	}
+	return nil
+}
+
+type IsPrimeRequest struct {
+	// A decimal integer of any size.
+	Number string `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	// Miller-Rabin rounds with random bases for numbers above 64 bits, on
+	// top of the Baillie-PSW test. 0 means 20. Smaller numbers are tested
+	// exactly.
+	Rounds               uint32   `protobuf:"varint,2,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IsPrimeRequest) Reset()         { *m = IsPrimeRequest{} }
+func (m *IsPrimeRequest) String() string { return proto.CompactTextString(m) }
+func (*IsPrimeRequest) ProtoMessage()    {}
+func (*IsPrimeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{19}
+}
+
+func (m *IsPrimeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IsPrimeRequest.Unmarshal(m, b)
+}
+func (m *IsPrimeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IsPrimeRequest.Marshal(b, m, deterministic)
+}
+func (m *IsPrimeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IsPrimeRequest.Merge(m, src)
+}
+func (m *IsPrimeRequest) XXX_Size() int {
+	return xxx_messageInfo_IsPrimeRequest.Size(m)
+}
+func (m *IsPrimeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IsPrimeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IsPrimeRequest proto.InternalMessageInfo
+
+func (m *IsPrimeRequest) GetNumber() string {
+	if m != nil {
+		return m.Number
+	}
+	return ""
+}
+
+func (m *IsPrimeRequest) GetRounds() uint32 {
+	if m != nil {
+		return m.Rounds
+	}
+	return 0
+}
+
+type IsPrimeResponse struct {
+	Prime bool `protobuf:"varint,1,opt,name=prime,proto3" json:"prime,omitempty"`
+	// False if a composite number could have been taken for a prime.
+	Certain bool `protobuf:"varint,2,opt,name=certain,proto3" json:"certain,omitempty"`
+	// Upper bound of the probability that a composite number was reported
+	// prime; 0 if certain.
+	ErrorProbability     float64  `protobuf:"fixed64,3,opt,name=error_probability,json=errorProbability,proto3" json:"error_probability,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IsPrimeResponse) Reset()         { *m = IsPrimeResponse{} }
+func (m *IsPrimeResponse) String() string { return proto.CompactTextString(m) }
+func (*IsPrimeResponse) ProtoMessage()    {}
+func (*IsPrimeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{20}
+}
+
+func (m *IsPrimeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IsPrimeResponse.Unmarshal(m, b)
+}
+func (m *IsPrimeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IsPrimeResponse.Marshal(b, m, deterministic)
+}
+func (m *IsPrimeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IsPrimeResponse.Merge(m, src)
+}
+func (m *IsPrimeResponse) XXX_Size() int {
+	return xxx_messageInfo_IsPrimeResponse.Size(m)
+}
+func (m *IsPrimeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IsPrimeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IsPrimeResponse proto.InternalMessageInfo
+
+func (m *IsPrimeResponse) GetPrime() bool {
+	if m != nil {
+		return m.Prime
+	}
+	return false
+}
+
+func (m *IsPrimeResponse) GetCertain() bool {
+	if m != nil {
+		return m.Certain
+	}
+	return false
+}
+
+func (m *IsPrimeResponse) GetErrorProbability() float64 {
+	if m != nil {
+		return m.ErrorProbability
+	}
+	return 0
+}
+
+type NextPrimeRequest struct {
+	// A decimal integer of any size.
+	Number string `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	// As in IsPrimeRequest.
+	Rounds               uint32   `protobuf:"varint,2,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NextPrimeRequest) Reset()         { *m = NextPrimeRequest{} }
+func (m *NextPrimeRequest) String() string { return proto.CompactTextString(m) }
+func (*NextPrimeRequest) ProtoMessage()    {}
+func (*NextPrimeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{21}
+}
+
+func (m *NextPrimeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NextPrimeRequest.Unmarshal(m, b)
+}
+func (m *NextPrimeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NextPrimeRequest.Marshal(b, m, deterministic)
+}
+func (m *NextPrimeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NextPrimeRequest.Merge(m, src)
+}
+func (m *NextPrimeRequest) XXX_Size() int {
+	return xxx_messageInfo_NextPrimeRequest.Size(m)
+}
+func (m *NextPrimeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NextPrimeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NextPrimeRequest proto.InternalMessageInfo
+
+func (m *NextPrimeRequest) GetNumber() string {
+	if m != nil {
+		return m.Number
+	}
+	return ""
+}
+
+func (m *NextPrimeRequest) GetRounds() uint32 {
+	if m != nil {
+		return m.Rounds
+	}
+	return 0
+}
+
+type NextPrimeResponse struct {
+	// The smallest prime above, or for PrevPrime the largest prime below,
+	// the number.
+	Prime string `protobuf:"bytes,1,opt,name=prime,proto3" json:"prime,omitempty"`
+	// As in IsPrimeResponse.
+	Certain              bool     `protobuf:"varint,2,opt,name=certain,proto3" json:"certain,omitempty"`
+	ErrorProbability     float64  `protobuf:"fixed64,3,opt,name=error_probability,json=errorProbability,proto3" json:"error_probability,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NextPrimeResponse) Reset()         { *m = NextPrimeResponse{} }
+func (m *NextPrimeResponse) String() string { return proto.CompactTextString(m) }
+func (*NextPrimeResponse) ProtoMessage()    {}
+func (*NextPrimeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{22}
+}
+
+func (m *NextPrimeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NextPrimeResponse.Unmarshal(m, b)
+}
+func (m *NextPrimeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NextPrimeResponse.Marshal(b, m, deterministic)
+}
+func (m *NextPrimeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NextPrimeResponse.Merge(m, src)
+}
+func (m *NextPrimeResponse) XXX_Size() int {
+	return xxx_messageInfo_NextPrimeResponse.Size(m)
+}
+func (m *NextPrimeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NextPrimeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NextPrimeResponse proto.InternalMessageInfo
+
+func (m *NextPrimeResponse) GetPrime() string {
+	if m != nil {
+		return m.Prime
+	}
+	return ""
+}
+
+func (m *NextPrimeResponse) GetCertain() bool {
+	if m != nil {
+		return m.Certain
+	}
+	return false
+}
+
+func (m *NextPrimeResponse) GetErrorProbability() float64 {
+	if m != nil {
+		return m.ErrorProbability
+	}
+	return 0
+}
+
+type ListPrimesRequest struct {
+	// The primes from from to to, both included, are listed.
+	From                 uint64   `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   uint64   `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPrimesRequest) Reset()         { *m = ListPrimesRequest{} }
+func (m *ListPrimesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListPrimesRequest) ProtoMessage()    {}
+func (*ListPrimesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{23}
+}
+
+func (m *ListPrimesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPrimesRequest.Unmarshal(m, b)
+}
+func (m *ListPrimesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPrimesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListPrimesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPrimesRequest.Merge(m, src)
+}
+func (m *ListPrimesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListPrimesRequest.Size(m)
+}
+func (m *ListPrimesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPrimesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPrimesRequest proto.InternalMessageInfo
+
+func (m *ListPrimesRequest) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *ListPrimesRequest) GetTo() uint64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+type ListPrimesResponse struct {
+	// The next primes in ascending order.
+	Primes               []uint64 `protobuf:"varint,1,rep,packed,name=primes,proto3" json:"primes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPrimesResponse) Reset()         { *m = ListPrimesResponse{} }
+func (m *ListPrimesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListPrimesResponse) ProtoMessage()    {}
+func (*ListPrimesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{24}
+}
+
+func (m *ListPrimesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPrimesResponse.Unmarshal(m, b)
+}
+func (m *ListPrimesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPrimesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListPrimesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPrimesResponse.Merge(m, src)
+}
+func (m *ListPrimesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListPrimesResponse.Size(m)
+}
+func (m *ListPrimesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPrimesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPrimesResponse proto.InternalMessageInfo
+
+func (m *ListPrimesResponse) GetPrimes() []uint64 {
+	if m != nil {
+		return m.Primes
+	}
+	return nil
+}
+
+type ComputeStatisticsRequest struct {
+	// The next numbers of the stream. They must be finite.
+	Numbers []float64 `protobuf:"fixed64,1,rep,packed,name=numbers,proto3" json:"numbers,omitempty"`
+	// Percentiles between 0 and 100 to compute, e.g. 90, 99 and 99.9. Only
+	// the first message may set them.
+	Percentiles          []float64 `protobuf:"fixed64,2,rep,packed,name=percentiles,proto3" json:"percentiles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ComputeStatisticsRequest) Reset()         { *m = ComputeStatisticsRequest{} }
+func (m *ComputeStatisticsRequest) String() string { return proto.CompactTextString(m) }
+func (*ComputeStatisticsRequest) ProtoMessage()    {}
+func (*ComputeStatisticsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{25}
+}
+
+func (m *ComputeStatisticsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ComputeStatisticsRequest.Unmarshal(m, b)
+}
+func (m *ComputeStatisticsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ComputeStatisticsRequest.Marshal(b, m, deterministic)
+}
+func (m *ComputeStatisticsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ComputeStatisticsRequest.Merge(m, src)
+}
+func (m *ComputeStatisticsRequest) XXX_Size() int {
+	return xxx_messageInfo_ComputeStatisticsRequest.Size(m)
+}
+func (m *ComputeStatisticsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ComputeStatisticsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ComputeStatisticsRequest proto.InternalMessageInfo
+
+func (m *ComputeStatisticsRequest) GetNumbers() []float64 {
+	if m != nil {
+		return m.Numbers
+	}
+	return nil
+}
+
+func (m *ComputeStatisticsRequest) GetPercentiles() []float64 {
+	if m != nil {
+		return m.Percentiles
+	}
+	return nil
+}
+
+type Percentile struct {
+	Percentile           float64  `protobuf:"fixed64,1,opt,name=percentile,proto3" json:"percentile,omitempty"`
+	Value                float64  `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Percentile) Reset()         { *m = Percentile{} }
+func (m *Percentile) String() string { return proto.CompactTextString(m) }
+func (*Percentile) ProtoMessage()    {}
+func (*Percentile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{26}
+}
+
+func (m *Percentile) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Percentile.Unmarshal(m, b)
+}
+func (m *Percentile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Percentile.Marshal(b, m, deterministic)
+}
+func (m *Percentile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Percentile.Merge(m, src)
+}
+func (m *Percentile) XXX_Size() int {
+	return xxx_messageInfo_Percentile.Size(m)
+}
+func (m *Percentile) XXX_DiscardUnknown() {
+	xxx_messageInfo_Percentile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Percentile proto.InternalMessageInfo
+
+func (m *Percentile) GetPercentile() float64 {
+	if m != nil {
+		return m.Percentile
+	}
+	return 0
+}
+
+func (m *Percentile) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type ComputeStatisticsResponse struct {
+	Count uint64  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Sum   float64 `protobuf:"fixed64,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	Mean  float64 `protobuf:"fixed64,3,opt,name=mean,proto3" json:"mean,omitempty"`
+	Min   float64 `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max   float64 `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+	// Population variance and standard deviation.
+	Variance          float64 `protobuf:"fixed64,6,opt,name=variance,proto3" json:"variance,omitempty"`
+	StandardDeviation float64 `protobuf:"fixed64,7,opt,name=standard_deviation,json=standardDeviation,proto3" json:"standard_deviation,omitempty"`
+	Median            float64 `protobuf:"fixed64,8,opt,name=median,proto3" json:"median,omitempty"`
+	// The requested percentiles in the order they were asked for.
+	Percentiles []*Percentile `protobuf:"bytes,9,rep,name=percentiles,proto3" json:"percentiles,omitempty"`
+	// False if the stream was too long to keep every number: median and
+	// percentiles are then estimates within relative_error of a value of
+	// the stream near the right rank.
+	Exact                bool     `protobuf:"varint,10,opt,name=exact,proto3" json:"exact,omitempty"`
+	RelativeError        float64  `protobuf:"fixed64,11,opt,name=relative_error,json=relativeError,proto3" json:"relative_error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ComputeStatisticsResponse) Reset()         { *m = ComputeStatisticsResponse{} }
+func (m *ComputeStatisticsResponse) String() string { return proto.CompactTextString(m) }
+func (*ComputeStatisticsResponse) ProtoMessage()    {}
+func (*ComputeStatisticsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{27}
+}
+
+func (m *ComputeStatisticsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ComputeStatisticsResponse.Unmarshal(m, b)
+}
+func (m *ComputeStatisticsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ComputeStatisticsResponse.Marshal(b, m, deterministic)
+}
+func (m *ComputeStatisticsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ComputeStatisticsResponse.Merge(m, src)
+}
+func (m *ComputeStatisticsResponse) XXX_Size() int {
+	return xxx_messageInfo_ComputeStatisticsResponse.Size(m)
+}
+func (m *ComputeStatisticsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ComputeStatisticsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ComputeStatisticsResponse proto.InternalMessageInfo
+
+func (m *ComputeStatisticsResponse) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ComputeStatisticsResponse) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *ComputeStatisticsResponse) GetMean() float64 {
+	if m != nil {
+		return m.Mean
+	}
+	return 0
+}
+
+func (m *ComputeStatisticsResponse) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *ComputeStatisticsResponse) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *ComputeStatisticsResponse) GetVariance() float64 {
+	if m != nil {
+		return m.Variance
+	}
+	return 0
+}
+
+func (m *ComputeStatisticsResponse) GetStandardDeviation() float64 {
+	if m != nil {
+		return m.StandardDeviation
+	}
+	return 0
+}
+
+func (m *ComputeStatisticsResponse) GetMedian() float64 {
+	if m != nil {
+		return m.Median
+	}
+	return 0
+}
+
+func (m *ComputeStatisticsResponse) GetPercentiles() []*Percentile {
+	if m != nil {
+		return m.Percentiles
+	}
+	return nil
+}
+
+func (m *ComputeStatisticsResponse) GetExact() bool {
+	if m != nil {
+		return m.Exact
+	}
+	return false
+}
+
+func (m *ComputeStatisticsResponse) GetRelativeError() float64 {
+	if m != nil {
+		return m.RelativeError
+	}
+	return 0
+}
+
+type RunningAggregateRequest struct {
+	// The settings are read from the first message and must not be set in
+	// later ones.
+	Aggregates []Aggregate `protobuf:"varint,1,rep,packed,name=aggregates,proto3,enum=calculator.Aggregate" json:"aggregates,omitempty"`
+	Window     WindowType  `protobuf:"varint,2,opt,name=window,proto3,enum=calculator.WindowType" json:"window,omitempty"`
+	WindowSize uint32      `protobuf:"varint,3,opt,name=window_size,json=windowSize,proto3" json:"window_size,omitempty"`
+	WindowMs   uint32      `protobuf:"varint,4,opt,name=window_ms,json=windowMs,proto3" json:"window_ms,omitempty"`
+	// Between 0 and 1; higher values follow the latest numbers more closely.
+	EwmaAlpha float64  `protobuf:"fixed64,5,opt,name=ewma_alpha,json=ewmaAlpha,proto3" json:"ewma_alpha,omitempty"`
+	Emit      EmitMode `protobuf:"varint,6,opt,name=emit,proto3,enum=calculator.EmitMode" json:"emit,omitempty"`
+	// The next numbers. They must be finite.
+	Numbers              []float64 `protobuf:"fixed64,7,rep,packed,name=numbers,proto3" json:"numbers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RunningAggregateRequest) Reset()         { *m = RunningAggregateRequest{} }
+func (m *RunningAggregateRequest) String() string { return proto.CompactTextString(m) }
+func (*RunningAggregateRequest) ProtoMessage()    {}
+func (*RunningAggregateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{28}
+}
+
+func (m *RunningAggregateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunningAggregateRequest.Unmarshal(m, b)
+}
+func (m *RunningAggregateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunningAggregateRequest.Marshal(b, m, deterministic)
+}
+func (m *RunningAggregateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunningAggregateRequest.Merge(m, src)
+}
+func (m *RunningAggregateRequest) XXX_Size() int {
+	return xxx_messageInfo_RunningAggregateRequest.Size(m)
+}
+func (m *RunningAggregateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunningAggregateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunningAggregateRequest proto.InternalMessageInfo
+
+func (m *RunningAggregateRequest) GetAggregates() []Aggregate {
+	if m != nil {
+		return m.Aggregates
+	}
+	return nil
+}
+
+func (m *RunningAggregateRequest) GetWindow() WindowType {
+	if m != nil {
+		return m.Window
+	}
+	return WindowType_WINDOW_TYPE_CUMULATIVE
+}
+
+func (m *RunningAggregateRequest) GetWindowSize() uint32 {
+	if m != nil {
+		return m.WindowSize
+	}
+	return 0
+}
+
+func (m *RunningAggregateRequest) GetWindowMs() uint32 {
+	if m != nil {
+		return m.WindowMs
+	}
+	return 0
+}
+
+func (m *RunningAggregateRequest) GetEwmaAlpha() float64 {
+	if m != nil {
+		return m.EwmaAlpha
+	}
+	return 0
+}
+
+func (m *RunningAggregateRequest) GetEmit() EmitMode {
+	if m != nil {
+		return m.Emit
+	}
+	return EmitMode_EMIT_MODE_EVERY_MESSAGE
+}
+
+func (m *RunningAggregateRequest) GetNumbers() []float64 {
+	if m != nil {
+		return m.Numbers
+	}
+	return nil
+}
+
+type AggregateValue struct {
+	Aggregate            Aggregate `protobuf:"varint,1,opt,name=aggregate,proto3,enum=calculator.Aggregate" json:"aggregate,omitempty"`
+	Value                float64   `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *AggregateValue) Reset()         { *m = AggregateValue{} }
+func (m *AggregateValue) String() string { return proto.CompactTextString(m) }
+func (*AggregateValue) ProtoMessage()    {}
+func (*AggregateValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{29}
+}
+
+func (m *AggregateValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AggregateValue.Unmarshal(m, b)
+}
+func (m *AggregateValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AggregateValue.Marshal(b, m, deterministic)
+}
+func (m *AggregateValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregateValue.Merge(m, src)
+}
+func (m *AggregateValue) XXX_Size() int {
+	return xxx_messageInfo_AggregateValue.Size(m)
+}
+func (m *AggregateValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregateValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregateValue proto.InternalMessageInfo
+
+func (m *AggregateValue) GetAggregate() Aggregate {
+	if m != nil {
+		return m.Aggregate
+	}
+	return Aggregate_AGGREGATE_UNSPECIFIED
+}
+
+func (m *AggregateValue) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type RunningAggregateResponse struct {
+	// The requested aggregates in the order they were asked for.
+	Values []*AggregateValue `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	// Tumbling windows: when the window started, in Unix milliseconds, and
+	// whether it has ended, making these its final values. A window without
+	// numbers sends nothing.
+	WindowStartMs        int64    `protobuf:"varint,2,opt,name=window_start_ms,json=windowStartMs,proto3" json:"window_start_ms,omitempty"`
+	WindowClosed         bool     `protobuf:"varint,3,opt,name=window_closed,json=windowClosed,proto3" json:"window_closed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RunningAggregateResponse) Reset()         { *m = RunningAggregateResponse{} }
+func (m *RunningAggregateResponse) String() string { return proto.CompactTextString(m) }
+func (*RunningAggregateResponse) ProtoMessage()    {}
+func (*RunningAggregateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{30}
+}
+
+func (m *RunningAggregateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunningAggregateResponse.Unmarshal(m, b)
+}
+func (m *RunningAggregateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunningAggregateResponse.Marshal(b, m, deterministic)
+}
+func (m *RunningAggregateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunningAggregateResponse.Merge(m, src)
+}
+func (m *RunningAggregateResponse) XXX_Size() int {
+	return xxx_messageInfo_RunningAggregateResponse.Size(m)
+}
+func (m *RunningAggregateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunningAggregateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunningAggregateResponse proto.InternalMessageInfo
+
+func (m *RunningAggregateResponse) GetValues() []*AggregateValue {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *RunningAggregateResponse) GetWindowStartMs() int64 {
+	if m != nil {
+		return m.WindowStartMs
+	}
+	return 0
+}
+
+func (m *RunningAggregateResponse) GetWindowClosed() bool {
+	if m != nil {
+		return m.WindowClosed
+	}
+	return false
+}
+
+type EvaluateRequest struct {
+	// An infix expression such as "(3 + 4) * sqrt(16) / 2^3", or several
+	// separated by semicolons, which may assign variables: "x = 5; y = x * 2".
+	Expression string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	// A session from CreateSession keeps variables and ans between calls.
+	// Without one they only last for the call.
+	SessionId            string   `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EvaluateRequest) Reset()         { *m = EvaluateRequest{} }
+func (m *EvaluateRequest) String() string { return proto.CompactTextString(m) }
+func (*EvaluateRequest) ProtoMessage()    {}
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{31}
+}
+
+func (m *EvaluateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvaluateRequest.Unmarshal(m, b)
+}
+func (m *EvaluateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvaluateRequest.Marshal(b, m, deterministic)
+}
+func (m *EvaluateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvaluateRequest.Merge(m, src)
+}
+func (m *EvaluateRequest) XXX_Size() int {
+	return xxx_messageInfo_EvaluateRequest.Size(m)
+}
+func (m *EvaluateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvaluateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvaluateRequest proto.InternalMessageInfo
+
+func (m *EvaluateRequest) GetExpression() string {
+	if m != nil {
+		return m.Expression
+	}
+	return ""
+}
+
+func (m *EvaluateRequest) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+type EvaluateResponse struct {
+	Result               float64  `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EvaluateResponse) Reset()         { *m = EvaluateResponse{} }
+func (m *EvaluateResponse) String() string { return proto.CompactTextString(m) }
+func (*EvaluateResponse) ProtoMessage()    {}
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{32}
+}
+
+func (m *EvaluateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvaluateResponse.Unmarshal(m, b)
+}
+func (m *EvaluateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvaluateResponse.Marshal(b, m, deterministic)
+}
+func (m *EvaluateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvaluateResponse.Merge(m, src)
+}
+func (m *EvaluateResponse) XXX_Size() int {
+	return xxx_messageInfo_EvaluateResponse.Size(m)
+}
+func (m *EvaluateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvaluateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvaluateResponse proto.InternalMessageInfo
+
+func (m *EvaluateResponse) GetResult() float64 {
+	if m != nil {
+		return m.Result
+	}
+	return 0
+}
+
+type CreateSessionRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSessionRequest) Reset()         { *m = CreateSessionRequest{} }
+func (m *CreateSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSessionRequest) ProtoMessage()    {}
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{33}
+}
+
+func (m *CreateSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSessionRequest.Unmarshal(m, b)
+}
+func (m *CreateSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSessionRequest.Merge(m, src)
+}
+func (m *CreateSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateSessionRequest.Size(m)
+}
+func (m *CreateSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSessionRequest proto.InternalMessageInfo
+
+type CreateSessionResponse struct {
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// The session ends after this long without being used.
+	TtlSeconds           uint32   `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSessionResponse) Reset()         { *m = CreateSessionResponse{} }
+func (m *CreateSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateSessionResponse) ProtoMessage()    {}
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{34}
+}
+
+func (m *CreateSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSessionResponse.Unmarshal(m, b)
+}
+func (m *CreateSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSessionResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSessionResponse.Merge(m, src)
+}
+func (m *CreateSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateSessionResponse.Size(m)
+}
+func (m *CreateSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSessionResponse proto.InternalMessageInfo
+
+func (m *CreateSessionResponse) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+func (m *CreateSessionResponse) GetTtlSeconds() uint32 {
+	if m != nil {
+		return m.TtlSeconds
+	}
+	return 0
+}
+
+type Variable struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value                float64  `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Variable) Reset()         { *m = Variable{} }
+func (m *Variable) String() string { return proto.CompactTextString(m) }
+func (*Variable) ProtoMessage()    {}
+func (*Variable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{35}
+}
+
+func (m *Variable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Variable.Unmarshal(m, b)
+}
+func (m *Variable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Variable.Marshal(b, m, deterministic)
+}
+func (m *Variable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Variable.Merge(m, src)
+}
+func (m *Variable) XXX_Size() int {
+	return xxx_messageInfo_Variable.Size(m)
+}
+func (m *Variable) XXX_DiscardUnknown() {
+	xxx_messageInfo_Variable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Variable proto.InternalMessageInfo
+
+func (m *Variable) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Variable) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type ListVariablesRequest struct {
+	SessionId            string   `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListVariablesRequest) Reset()         { *m = ListVariablesRequest{} }
+func (m *ListVariablesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListVariablesRequest) ProtoMessage()    {}
+func (*ListVariablesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{36}
+}
+
+func (m *ListVariablesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListVariablesRequest.Unmarshal(m, b)
+}
+func (m *ListVariablesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListVariablesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListVariablesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListVariablesRequest.Merge(m, src)
+}
+func (m *ListVariablesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListVariablesRequest.Size(m)
+}
+func (m *ListVariablesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListVariablesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListVariablesRequest proto.InternalMessageInfo
+
+func (m *ListVariablesRequest) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+type ListVariablesResponse struct {
+	// The variables of the session sorted by name, and ans if set.
+	Variables            []*Variable `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListVariablesResponse) Reset()         { *m = ListVariablesResponse{} }
+func (m *ListVariablesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListVariablesResponse) ProtoMessage()    {}
+func (*ListVariablesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{37}
+}
+
+func (m *ListVariablesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListVariablesResponse.Unmarshal(m, b)
+}
+func (m *ListVariablesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListVariablesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListVariablesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListVariablesResponse.Merge(m, src)
+}
+func (m *ListVariablesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListVariablesResponse.Size(m)
+}
+func (m *ListVariablesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListVariablesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListVariablesResponse proto.InternalMessageInfo
+
+func (m *ListVariablesResponse) GetVariables() []*Variable {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+type DeleteSessionRequest struct {
+	SessionId            string   `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSessionRequest) Reset()         { *m = DeleteSessionRequest{} }
+func (m *DeleteSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSessionRequest) ProtoMessage()    {}
+func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{38}
+}
+
+func (m *DeleteSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSessionRequest.Unmarshal(m, b)
+}
+func (m *DeleteSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSessionRequest.Merge(m, src)
+}
+func (m *DeleteSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSessionRequest.Size(m)
+}
+func (m *DeleteSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSessionRequest proto.InternalMessageInfo
+
+func (m *DeleteSessionRequest) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+type DeleteSessionResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSessionResponse) Reset()         { *m = DeleteSessionResponse{} }
+func (m *DeleteSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteSessionResponse) ProtoMessage()    {}
+func (*DeleteSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{39}
+}
+
+func (m *DeleteSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSessionResponse.Unmarshal(m, b)
+}
+func (m *DeleteSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSessionResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSessionResponse.Merge(m, src)
+}
+func (m *DeleteSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteSessionResponse.Size(m)
+}
+func (m *DeleteSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSessionResponse proto.InternalMessageInfo
+
+// A user-defined function, such as f(x, y) = x^2 + y.
+type Function struct {
+	Name       string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Parameters []string `protobuf:"bytes,2,rep,name=parameters,proto3" json:"parameters,omitempty"`
+	Body       string   `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	// The whole definition: name(parameters) = body.
+	Definition           string   `protobuf:"bytes,4,opt,name=definition,proto3" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Function) Reset()         { *m = Function{} }
+func (m *Function) String() string { return proto.CompactTextString(m) }
+func (*Function) ProtoMessage()    {}
+func (*Function) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{40}
+}
+
+func (m *Function) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Function.Unmarshal(m, b)
+}
+func (m *Function) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Function.Marshal(b, m, deterministic)
+}
+func (m *Function) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Function.Merge(m, src)
+}
+func (m *Function) XXX_Size() int {
+	return xxx_messageInfo_Function.Size(m)
+}
+func (m *Function) XXX_DiscardUnknown() {
+	xxx_messageInfo_Function.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Function proto.InternalMessageInfo
+
+func (m *Function) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Function) GetParameters() []string {
+	if m != nil {
+		return m.Parameters
+	}
+	return nil
+}
+
+func (m *Function) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *Function) GetDefinition() string {
+	if m != nil {
+		return m.Definition
+	}
+	return ""
+}
+
+type DefineFunctionRequest struct {
+	// Such as "f(x, y) = x^2 + y". The body may use the parameters, the
+	// constants, the built-in functions and functions defined before.
+	Definition string `protobuf:"bytes,1,opt,name=definition,proto3" json:"definition,omitempty"`
+	// An existing function is only changed if replace is set.
+	Replace              bool     `protobuf:"varint,2,opt,name=replace,proto3" json:"replace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DefineFunctionRequest) Reset()         { *m = DefineFunctionRequest{} }
+func (m *DefineFunctionRequest) String() string { return proto.CompactTextString(m) }
+func (*DefineFunctionRequest) ProtoMessage()    {}
+func (*DefineFunctionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{41}
+}
+
+func (m *DefineFunctionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DefineFunctionRequest.Unmarshal(m, b)
+}
+func (m *DefineFunctionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DefineFunctionRequest.Marshal(b, m, deterministic)
+}
+func (m *DefineFunctionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DefineFunctionRequest.Merge(m, src)
+}
+func (m *DefineFunctionRequest) XXX_Size() int {
+	return xxx_messageInfo_DefineFunctionRequest.Size(m)
+}
+func (m *DefineFunctionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DefineFunctionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DefineFunctionRequest proto.InternalMessageInfo
+
+func (m *DefineFunctionRequest) GetDefinition() string {
+	if m != nil {
+		return m.Definition
+	}
+	return ""
+}
+
+func (m *DefineFunctionRequest) GetReplace() bool {
+	if m != nil {
+		return m.Replace
+	}
+	return false
+}
+
+type DefineFunctionResponse struct {
+	Function             *Function `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	Replaced             bool      `protobuf:"varint,2,opt,name=replaced,proto3" json:"replaced,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *DefineFunctionResponse) Reset()         { *m = DefineFunctionResponse{} }
+func (m *DefineFunctionResponse) String() string { return proto.CompactTextString(m) }
+func (*DefineFunctionResponse) ProtoMessage()    {}
+func (*DefineFunctionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{42}
+}
+
+func (m *DefineFunctionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DefineFunctionResponse.Unmarshal(m, b)
+}
+func (m *DefineFunctionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DefineFunctionResponse.Marshal(b, m, deterministic)
+}
+func (m *DefineFunctionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DefineFunctionResponse.Merge(m, src)
+}
+func (m *DefineFunctionResponse) XXX_Size() int {
+	return xxx_messageInfo_DefineFunctionResponse.Size(m)
+}
+func (m *DefineFunctionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DefineFunctionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DefineFunctionResponse proto.InternalMessageInfo
+
+func (m *DefineFunctionResponse) GetFunction() *Function {
+	if m != nil {
+		return m.Function
+	}
+	return nil
+}
+
+func (m *DefineFunctionResponse) GetReplaced() bool {
+	if m != nil {
+		return m.Replaced
+	}
+	return false
+}
+
+type ListFunctionsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListFunctionsRequest) Reset()         { *m = ListFunctionsRequest{} }
+func (m *ListFunctionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListFunctionsRequest) ProtoMessage()    {}
+func (*ListFunctionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{43}
+}
+
+func (m *ListFunctionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListFunctionsRequest.Unmarshal(m, b)
+}
+func (m *ListFunctionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListFunctionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListFunctionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListFunctionsRequest.Merge(m, src)
+}
+func (m *ListFunctionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListFunctionsRequest.Size(m)
+}
+func (m *ListFunctionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListFunctionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListFunctionsRequest proto.InternalMessageInfo
+
+type ListFunctionsResponse struct {
+	// Sorted by name.
+	Functions            []*Function `protobuf:"bytes,1,rep,name=functions,proto3" json:"functions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListFunctionsResponse) Reset()         { *m = ListFunctionsResponse{} }
+func (m *ListFunctionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListFunctionsResponse) ProtoMessage()    {}
+func (*ListFunctionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{44}
+}
+
+func (m *ListFunctionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListFunctionsResponse.Unmarshal(m, b)
+}
+func (m *ListFunctionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListFunctionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListFunctionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListFunctionsResponse.Merge(m, src)
+}
+func (m *ListFunctionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListFunctionsResponse.Size(m)
+}
+func (m *ListFunctionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListFunctionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListFunctionsResponse proto.InternalMessageInfo
+
+func (m *ListFunctionsResponse) GetFunctions() []*Function {
+	if m != nil {
+		return m.Functions
+	}
+	return nil
+}
+
+type DeleteFunctionRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteFunctionRequest) Reset()         { *m = DeleteFunctionRequest{} }
+func (m *DeleteFunctionRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteFunctionRequest) ProtoMessage()    {}
+func (*DeleteFunctionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{45}
+}
+
+func (m *DeleteFunctionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteFunctionRequest.Unmarshal(m, b)
+}
+func (m *DeleteFunctionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteFunctionRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteFunctionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteFunctionRequest.Merge(m, src)
+}
+func (m *DeleteFunctionRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteFunctionRequest.Size(m)
+}
+func (m *DeleteFunctionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteFunctionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteFunctionRequest proto.InternalMessageInfo
+
+func (m *DeleteFunctionRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type DeleteFunctionResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteFunctionResponse) Reset()         { *m = DeleteFunctionResponse{} }
+func (m *DeleteFunctionResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteFunctionResponse) ProtoMessage()    {}
+func (*DeleteFunctionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87e717c78a24322a, []int{46}
+}
+
+func (m *DeleteFunctionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteFunctionResponse.Unmarshal(m, b)
+}
+func (m *DeleteFunctionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteFunctionResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteFunctionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteFunctionResponse.Merge(m, src)
+}
+func (m *DeleteFunctionResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteFunctionResponse.Size(m)
+}
+func (m *DeleteFunctionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteFunctionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteFunctionResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterEnum("calculator.OverflowMode", OverflowMode_name, OverflowMode_value)
+	proto.RegisterEnum("calculator.BigOperation", BigOperation_name, BigOperation_value)
+	proto.RegisterEnum("calculator.Aggregate", Aggregate_name, Aggregate_value)
+	proto.RegisterEnum("calculator.WindowType", WindowType_name, WindowType_value)
+	proto.RegisterEnum("calculator.EmitMode", EmitMode_name, EmitMode_value)
+	proto.RegisterType((*SumRequest)(nil), "calculator.SumRequest")
+	proto.RegisterType((*SumResponse)(nil), "calculator.SumResponse")
+	proto.RegisterType((*PrimeNumberDecompositionRequest)(nil), "calculator.PrimeNumberDecompositionRequest")
+	proto.RegisterType((*PrimeNumberDecompositionResponse)(nil), "calculator.PrimeNumberDecompositionResponse")
+	proto.RegisterType((*ComputeAverageRequest)(nil), "calculator.ComputeAverageRequest")
+	proto.RegisterType((*ComputeAverageResponse)(nil), "calculator.ComputeAverageResponse")
+	proto.RegisterType((*FindMaximumRequest)(nil), "calculator.FindMaximumRequest")
+	proto.RegisterType((*FindMaximumResponse)(nil), "calculator.FindMaximumResponse")
+	proto.RegisterType((*SquareRootRequest)(nil), "calculator.SquareRootRequest")
+	proto.RegisterType((*SquareRootResponse)(nil), "calculator.SquareRootResponse")
+	proto.RegisterType((*SumWithDeadLineRequest)(nil), "calculator.SumWithDeadLineRequest")
+	proto.RegisterType((*SumWithDeadLineResponse)(nil), "calculator.SumWithDeadLineResponse")
+	proto.RegisterType((*BigIntRequest)(nil), "calculator.BigIntRequest")
+	proto.RegisterType((*BigIntResponse)(nil), "calculator.BigIntResponse")
+	proto.RegisterType((*BigRatRequest)(nil), "calculator.BigRatRequest")
+	proto.RegisterType((*BigRatResponse)(nil), "calculator.BigRatResponse")
+	proto.RegisterType((*FactorizeRequest)(nil), "calculator.FactorizeRequest")
+	proto.RegisterType((*FactorPower)(nil), "calculator.FactorPower")
+	proto.RegisterType((*FactorizeResponse)(nil), "calculator.FactorizeResponse")
+	proto.RegisterType((*IsPrimeRequest)(nil), "calculator.IsPrimeRequest")
+	proto.RegisterType((*IsPrimeResponse)(nil), "calculator.IsPrimeResponse")
+	proto.RegisterType((*NextPrimeRequest)(nil), "calculator.NextPrimeRequest")
+	proto.RegisterType((*NextPrimeResponse)(nil), "calculator.NextPrimeResponse")
+	proto.RegisterType((*ListPrimesRequest)(nil), "calculator.ListPrimesRequest")
+	proto.RegisterType((*ListPrimesResponse)(nil), "calculator.ListPrimesResponse")
+	proto.RegisterType((*ComputeStatisticsRequest)(nil), "calculator.ComputeStatisticsRequest")
+	proto.RegisterType((*Percentile)(nil), "calculator.Percentile")
+	proto.RegisterType((*ComputeStatisticsResponse)(nil), "calculator.ComputeStatisticsResponse")
+	proto.RegisterType((*RunningAggregateRequest)(nil), "calculator.RunningAggregateRequest")
+	proto.RegisterType((*AggregateValue)(nil), "calculator.AggregateValue")
+	proto.RegisterType((*RunningAggregateResponse)(nil), "calculator.RunningAggregateResponse")
+	proto.RegisterType((*EvaluateRequest)(nil), "calculator.EvaluateRequest")
+	proto.RegisterType((*EvaluateResponse)(nil), "calculator.EvaluateResponse")
+	proto.RegisterType((*CreateSessionRequest)(nil), "calculator.CreateSessionRequest")
+	proto.RegisterType((*CreateSessionResponse)(nil), "calculator.CreateSessionResponse")
+	proto.RegisterType((*Variable)(nil), "calculator.Variable")
+	proto.RegisterType((*ListVariablesRequest)(nil), "calculator.ListVariablesRequest")
+	proto.RegisterType((*ListVariablesResponse)(nil), "calculator.ListVariablesResponse")
+	proto.RegisterType((*DeleteSessionRequest)(nil), "calculator.DeleteSessionRequest")
+	proto.RegisterType((*DeleteSessionResponse)(nil), "calculator.DeleteSessionResponse")
+	proto.RegisterType((*Function)(nil), "calculator.Function")
+	proto.RegisterType((*DefineFunctionRequest)(nil), "calculator.DefineFunctionRequest")
+	proto.RegisterType((*DefineFunctionResponse)(nil), "calculator.DefineFunctionResponse")
+	proto.RegisterType((*ListFunctionsRequest)(nil), "calculator.ListFunctionsRequest")
+	proto.RegisterType((*ListFunctionsResponse)(nil), "calculator.ListFunctionsResponse")
+	proto.RegisterType((*DeleteFunctionRequest)(nil), "calculator.DeleteFunctionRequest")
+	proto.RegisterType((*DeleteFunctionResponse)(nil), "calculator.DeleteFunctionResponse")
+}
+
+func init() { proto.RegisterFile("calculatorpb/calculator.proto", fileDescriptor_87e717c78a24322a) }
+
+var fileDescriptor_87e717c78a24322a = []byte{
+	// 2277 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0xcd, 0x6f, 0xdb, 0xc8,
+	0x15, 0x0f, 0xfd, 0x29, 0x3d, 0xdb, 0x32, 0x3d, 0xf1, 0x07, 0xc3, 0xc4, 0x89, 0xcd, 0xec, 0x16,
+	0xae, 0xb3, 0x9b, 0x04, 0xde, 0xa6, 0xbb, 0x3d, 0x14, 0x58, 0xd9, 0x62, 0xbc, 0x6a, 0xac, 0x8f,
+	0x52, 0x92, 0xdd, 0x74, 0x0b, 0x10, 0xb4, 0x38, 0x76, 0x08, 0x88, 0xa4, 0x96, 0x1c, 0xda, 0x4e,
+	0x80, 0x16, 0x3d, 0xf7, 0xd6, 0x73, 0xdb, 0x43, 0xff, 0xa8, 0xfe, 0x07, 0xbd, 0xf4, 0xd6, 0x3f,
+	0xa1, 0x98, 0xe1, 0x90, 0x1c, 0x52, 0x94, 0xb2, 0x40, 0xb7, 0xc5, 0xde, 0xf8, 0x7e, 0xef, 0x73,
+	0xde, 0xbc, 0x99, 0x79, 0x7a, 0x82, 0xdd, 0xa1, 0x35, 0x1a, 0x46, 0x23, 0x8b, 0xf8, 0xc1, 0xf8,
+	0xf2, 0x45, 0x46, 0x3c, 0x1f, 0x07, 0x3e, 0xf1, 0x11, 0x64, 0x88, 0xf6, 0x67, 0x09, 0xa0, 0x17,
+	0xb9, 0x06, 0xfe, 0x2e, 0xc2, 0x21, 0x41, 0xfb, 0xb0, 0x7a, 0xe5, 0x04, 0x21, 0x31, 0xbd, 0xc8,
+	0xbd, 0xc4, 0x81, 0x22, 0xed, 0x49, 0x07, 0x8b, 0xc6, 0x0a, 0xc3, 0xda, 0x0c, 0xa2, 0x22, 0x21,
+	0x1e, 0xfa, 0x9e, 0x6d, 0xc6, 0x22, 0x73, 0xb1, 0x48, 0x8c, 0x0d, 0x98, 0xc8, 0x2f, 0x61, 0xcd,
+	0xbf, 0xc1, 0xc1, 0xd5, 0xc8, 0xbf, 0x35, 0x5d, 0xdf, 0xc6, 0xca, 0xfc, 0x9e, 0x74, 0x50, 0x3b,
+	0x52, 0x9e, 0x0b, 0xa1, 0x74, 0xb8, 0x40, 0xcb, 0xb7, 0xb1, 0xb1, 0xea, 0x0b, 0x94, 0x46, 0x60,
+	0x85, 0x85, 0x14, 0x8e, 0x7d, 0x2f, 0xc4, 0x68, 0x17, 0x20, 0x8c, 0x5c, 0x33, 0xc0, 0x61, 0x34,
+	0x22, 0x3c, 0xa2, 0x6a, 0xc8, 0x04, 0xa2, 0x11, 0x41, 0x3f, 0x81, 0xf5, 0x5b, 0xc7, 0xc6, 0xa6,
+	0x20, 0x43, 0x43, 0x9a, 0x37, 0xd6, 0x28, 0xdc, 0x4b, 0xe5, 0x1e, 0x03, 0x24, 0x5e, 0xb0, 0xcd,
+	0x22, 0xaa, 0x18, 0x02, 0xa2, 0xfd, 0x02, 0x9e, 0x74, 0x03, 0xc7, 0xc5, 0xf1, 0x32, 0x1b, 0x78,
+	0xe8, 0xbb, 0x63, 0x3f, 0x74, 0x88, 0xe3, 0x7b, 0x49, 0x76, 0xb6, 0x61, 0x49, 0xc8, 0xcb, 0xbc,
+	0xc1, 0x29, 0x4d, 0x87, 0xbd, 0xe9, 0xaa, 0x7c, 0x15, 0xfb, 0xb0, 0x3a, 0xa6, 0x32, 0xe6, 0x95,
+	0x35, 0x24, 0x7e, 0x62, 0x61, 0x85, 0x61, 0xaf, 0x19, 0xa4, 0xbd, 0x80, 0xad, 0x13, 0xdf, 0x1d,
+	0x47, 0x04, 0xd7, 0x6f, 0x70, 0x60, 0x5d, 0xe3, 0x72, 0xbf, 0x8b, 0xa9, 0xdf, 0x23, 0xd8, 0x2e,
+	0x2a, 0x70, 0x6f, 0x0a, 0x2c, 0x5b, 0x31, 0xc4, 0x54, 0x24, 0x23, 0x21, 0xb5, 0xcf, 0x00, 0xbd,
+	0x76, 0x3c, 0xbb, 0x65, 0xdd, 0x39, 0x6e, 0xe4, 0x7e, 0xcc, 0xc3, 0x0b, 0xb8, 0x9f, 0x93, 0xce,
+	0xcc, 0xbb, 0x31, 0xc4, 0xe5, 0x13, 0x52, 0x7b, 0x06, 0x1b, 0xbd, 0xef, 0x22, 0x2b, 0xc0, 0x86,
+	0xef, 0x93, 0x8f, 0x59, 0x7f, 0x05, 0x48, 0x14, 0xe6, 0xc6, 0x9f, 0xc0, 0x4a, 0xcc, 0x37, 0x03,
+	0xdf, 0x27, 0x3c, 0x7e, 0x88, 0x21, 0x2a, 0xa8, 0xfd, 0x4d, 0x82, 0xed, 0x5e, 0xe4, 0x5e, 0x38,
+	0xe4, 0x5d, 0x03, 0x5b, 0xf6, 0x99, 0xe3, 0xe1, 0x1f, 0x55, 0xfd, 0xfe, 0x51, 0x82, 0x9d, 0x89,
+	0xf8, 0xfe, 0xbf, 0xc5, 0xfc, 0x7b, 0x58, 0x3b, 0x76, 0xae, 0x9b, 0x5e, 0xba, 0x05, 0x3f, 0x87,
+	0xaa, 0x3f, 0xc6, 0x81, 0x45, 0x6b, 0x52, 0x91, 0x26, 0x97, 0x73, 0xec, 0x5c, 0x77, 0x12, 0xbe,
+	0x91, 0x89, 0xa2, 0x55, 0x90, 0x2c, 0x16, 0x42, 0xd5, 0x90, 0x2c, 0x4a, 0x5d, 0x32, 0x6f, 0x55,
+	0x43, 0xba, 0x64, 0x55, 0xe0, 0xdb, 0xd1, 0x28, 0x0a, 0x95, 0x05, 0x86, 0x25, 0xa4, 0x76, 0x00,
+	0xb5, 0xc4, 0x3d, 0x5f, 0xf7, 0x36, 0x2c, 0x09, 0x6b, 0xae, 0x1a, 0x9c, 0xd2, 0xfe, 0x24, 0xb1,
+	0x48, 0x0d, 0xeb, 0x7f, 0x1a, 0xe9, 0xa7, 0x50, 0xb3, 0xf1, 0xd0, 0x71, 0xad, 0x91, 0x39, 0x1e,
+	0x59, 0x43, 0x1c, 0x07, 0xbc, 0x66, 0xac, 0x71, 0xb4, 0xcb, 0x40, 0xed, 0x18, 0x6a, 0x49, 0x2c,
+	0xb3, 0xc3, 0xa6, 0x4b, 0xe7, 0xaa, 0xdc, 0x65, 0x42, 0x6a, 0xa7, 0x20, 0xc7, 0xc7, 0xd9, 0xf9,
+	0x30, 0xe5, 0xfc, 0x56, 0x93, 0xfa, 0x47, 0x0f, 0xa1, 0x7a, 0x19, 0xd9, 0xd7, 0x98, 0x98, 0x6e,
+	0xc8, 0xec, 0xac, 0x19, 0x95, 0x18, 0x68, 0x85, 0x5a, 0x1d, 0x56, 0x62, 0x43, 0x5d, 0xff, 0x16,
+	0x07, 0xd4, 0x86, 0x70, 0x73, 0x54, 0x0d, 0x4e, 0x21, 0x15, 0x2a, 0xf8, 0x6e, 0xec, 0x7b, 0xd8,
+	0x23, 0x89, 0x89, 0x84, 0xd6, 0xfe, 0x2d, 0xc1, 0x86, 0x10, 0x0c, 0x5f, 0xd3, 0xe7, 0xb0, 0xc8,
+	0x6e, 0x1d, 0x66, 0x68, 0xe5, 0x68, 0x47, 0x4c, 0xae, 0xe0, 0xd1, 0x88, 0xa5, 0xd0, 0x26, 0x2c,
+	0x86, 0x04, 0x8f, 0xe3, 0x00, 0x17, 0x8c, 0x98, 0x40, 0x3f, 0x05, 0x39, 0xc0, 0xae, 0xe5, 0x78,
+	0x8e, 0x77, 0x6d, 0xda, 0xce, 0xb5, 0x43, 0x42, 0x96, 0xee, 0x35, 0x63, 0x3d, 0xc5, 0x1b, 0x0c,
+	0x46, 0x08, 0x16, 0x6c, 0xdf, 0xc3, 0x2c, 0xe5, 0x15, 0x83, 0x7d, 0xd3, 0xfa, 0x75, 0x3c, 0x7a,
+	0x4d, 0x8e, 0x30, 0xc1, 0xca, 0x22, 0xe3, 0x08, 0x08, 0xfa, 0x12, 0x20, 0xf2, 0xe2, 0x15, 0x62,
+	0x5b, 0x59, 0xda, 0x9b, 0x9f, 0x15, 0xa8, 0x20, 0xaa, 0x7d, 0x0d, 0xb5, 0x66, 0xc8, 0x2e, 0xe3,
+	0x8f, 0x25, 0x9f, 0x6e, 0xad, 0x1f, 0x79, 0x76, 0x92, 0x79, 0x4e, 0x69, 0x1e, 0xac, 0xa7, 0x16,
+	0x78, 0xc6, 0x36, 0xc5, 0x8c, 0x55, 0x92, 0xc4, 0x28, 0xb0, 0x3c, 0xc4, 0x01, 0xb1, 0x1c, 0x8f,
+	0x59, 0xa8, 0x18, 0x09, 0x89, 0x9e, 0xc1, 0x06, 0x0e, 0x02, 0x3f, 0x30, 0xc7, 0x81, 0x7f, 0x69,
+	0x5d, 0x3a, 0x23, 0x87, 0xbc, 0x67, 0xd9, 0x91, 0x0c, 0x99, 0x31, 0xba, 0x19, 0xae, 0x1d, 0x83,
+	0xdc, 0xc6, 0x77, 0xe4, 0xbf, 0x8a, 0x79, 0x0c, 0x1b, 0x82, 0x8d, 0xb2, 0xa8, 0xab, 0x3f, 0x70,
+	0xd4, 0x5f, 0xc2, 0xc6, 0x99, 0x13, 0xc6, 0x1e, 0xc3, 0x24, 0x6c, 0x04, 0x0b, 0x57, 0x81, 0x1f,
+	0xbf, 0x09, 0x0b, 0x06, 0xfb, 0x46, 0x35, 0x98, 0x23, 0x3e, 0xaf, 0x9d, 0x39, 0xe2, 0xd3, 0xf7,
+	0x47, 0x54, 0xcc, 0xce, 0x19, 0x0b, 0x2f, 0x54, 0xa4, 0xbd, 0xf9, 0x83, 0x05, 0x83, 0x53, 0xda,
+	0x39, 0x28, 0xfc, 0x85, 0xeb, 0x11, 0x8b, 0x38, 0x21, 0x71, 0x86, 0xa9, 0x37, 0x05, 0x96, 0xe3,
+	0xb4, 0xc4, 0x4a, 0x92, 0x91, 0x90, 0x68, 0x0f, 0x56, 0xc6, 0x38, 0x18, 0x62, 0x8f, 0x38, 0x23,
+	0x4c, 0x73, 0x45, 0xb9, 0x22, 0xa4, 0x1d, 0x03, 0x74, 0x53, 0x92, 0x56, 0x63, 0xc6, 0x4c, 0x1e,
+	0x9c, 0x0c, 0xa1, 0x99, 0xbc, 0xb1, 0x46, 0x11, 0x66, 0xcb, 0x90, 0x8c, 0x98, 0xd0, 0xfe, 0x31,
+	0x07, 0x0f, 0x4a, 0x82, 0xcb, 0xb2, 0x3f, 0xf4, 0x23, 0x8f, 0xf0, 0x64, 0xc4, 0x04, 0x92, 0x61,
+	0x3e, 0x8c, 0x5c, 0x6e, 0x87, 0x7e, 0xd2, 0x9c, 0xb9, 0xd8, 0xf2, 0x78, 0xa2, 0xd9, 0x37, 0x95,
+	0x72, 0x1d, 0x8f, 0x1d, 0x18, 0xc9, 0xa0, 0x9f, 0x0c, 0xb1, 0xee, 0x94, 0x45, 0x8e, 0x58, 0x77,
+	0xf4, 0xdc, 0xdf, 0x58, 0x81, 0x63, 0x79, 0x43, 0xac, 0x2c, 0x31, 0x38, 0xa5, 0xd1, 0xe7, 0x80,
+	0x42, 0x62, 0x79, 0xb6, 0x15, 0xd8, 0xa6, 0x8d, 0x6f, 0x9c, 0xf8, 0x2e, 0x5d, 0x66, 0x52, 0x1b,
+	0x09, 0xa7, 0x91, 0x30, 0x68, 0xf2, 0x5d, 0x6c, 0x3b, 0x96, 0xa7, 0x54, 0x98, 0x08, 0xa7, 0xd0,
+	0x57, 0xf9, 0x34, 0x56, 0xd9, 0x29, 0xdc, 0x16, 0x4f, 0x61, 0x96, 0xc3, 0x5c, 0x7a, 0xe9, 0xe2,
+	0xf1, 0x9d, 0x35, 0x24, 0x0a, 0xc4, 0x07, 0x86, 0x11, 0xf4, 0x16, 0x0e, 0xf0, 0xc8, 0x22, 0xce,
+	0x0d, 0x36, 0x59, 0x41, 0x29, 0x2b, 0xcc, 0xdf, 0x5a, 0x82, 0xea, 0x14, 0xd4, 0xfe, 0x3e, 0x07,
+	0x3b, 0x46, 0xe4, 0xd1, 0x1b, 0xa4, 0x7e, 0x7d, 0x1d, 0xe0, 0x6b, 0x8b, 0xa4, 0x07, 0xe3, 0x15,
+	0x80, 0x95, 0x60, 0xf1, 0xb6, 0xd7, 0x8e, 0xb6, 0xc4, 0x88, 0x32, 0x0d, 0x41, 0x10, 0x3d, 0x87,
+	0xa5, 0x5b, 0xc7, 0xb3, 0xfd, 0x5b, 0x96, 0xf9, 0x5a, 0x7e, 0x11, 0x17, 0x8c, 0xd3, 0x7f, 0x3f,
+	0xc6, 0x06, 0x97, 0xa2, 0x2d, 0x48, 0xfc, 0x65, 0x86, 0xce, 0x07, 0xcc, 0x2f, 0x36, 0x88, 0xa1,
+	0x9e, 0xf3, 0x01, 0xd3, 0x9b, 0x9b, 0x0b, 0xb8, 0xc9, 0x5b, 0x52, 0x89, 0x81, 0x56, 0x48, 0xdf,
+	0x78, 0x7c, 0xeb, 0x5a, 0xa6, 0x35, 0x1a, 0xbf, 0xb3, 0xf8, 0x9e, 0x55, 0x29, 0x52, 0xa7, 0x00,
+	0x3a, 0x80, 0x05, 0xec, 0x3a, 0x84, 0xed, 0x5a, 0xed, 0x68, 0x53, 0x0c, 0x45, 0x77, 0x1d, 0xc2,
+	0x1a, 0x0a, 0x26, 0x21, 0x56, 0xf8, 0x72, 0xae, 0xc2, 0xb5, 0x6f, 0xa1, 0x96, 0xae, 0xf4, 0x9c,
+	0x56, 0x23, 0xfa, 0x02, 0xaa, 0xe9, 0x82, 0xf9, 0xb3, 0x39, 0x25, 0x31, 0x99, 0xdc, 0x94, 0xc2,
+	0xfe, 0x8b, 0x04, 0xca, 0xe4, 0x06, 0xf0, 0xba, 0x3e, 0x82, 0x25, 0x26, 0x15, 0x67, 0x7f, 0xe5,
+	0x48, 0x2d, 0x75, 0xc2, 0x62, 0x32, 0xb8, 0x64, 0xdc, 0xd5, 0xc4, 0xe9, 0x24, 0x56, 0x90, 0xbe,
+	0x76, 0xac, 0xab, 0x61, 0x29, 0xa5, 0x68, 0x2b, 0x44, 0x4f, 0x81, 0x03, 0xe6, 0x70, 0xe4, 0x87,
+	0x69, 0x63, 0xb3, 0x1a, 0x83, 0x27, 0x0c, 0xd3, 0xba, 0xb0, 0xae, 0x53, 0xbb, 0x42, 0x55, 0x3c,
+	0x06, 0xc0, 0x77, 0xe3, 0x00, 0x87, 0x61, 0xd2, 0x33, 0x54, 0x0d, 0x01, 0x61, 0x4d, 0x57, 0xfc,
+	0x69, 0x3a, 0x36, 0x7f, 0xb0, 0xab, 0x1c, 0x69, 0xda, 0xda, 0x21, 0xc8, 0x99, 0xc5, 0xd2, 0x87,
+	0x5f, 0x4a, 0xfb, 0x95, 0x6d, 0xd8, 0x3c, 0x09, 0xb0, 0x45, 0x70, 0x2f, 0x56, 0xe7, 0x21, 0x68,
+	0x17, 0xb0, 0x55, 0xc0, 0x85, 0x86, 0x2f, 0xf3, 0x2d, 0x15, 0x7c, 0xd3, 0x4a, 0x23, 0x64, 0x64,
+	0xc6, 0xdd, 0x67, 0x72, 0xad, 0x03, 0x21, 0xa3, 0x5e, 0x8c, 0x68, 0x3f, 0x83, 0xca, 0x39, 0x3d,
+	0xd7, 0x97, 0x23, 0x4c, 0xef, 0x0a, 0xcf, 0x4a, 0x2f, 0x74, 0xf6, 0x3d, 0x65, 0x0b, 0x5f, 0xc1,
+	0x26, 0xbd, 0x65, 0x13, 0xcd, 0xf4, 0xce, 0x9c, 0x1d, 0x8d, 0xf6, 0x06, 0xb6, 0x0a, 0x6a, 0xe9,
+	0xae, 0x57, 0x6f, 0x12, 0x90, 0x6f, 0x7c, 0xae, 0x70, 0x13, 0x0d, 0x23, 0x13, 0xa3, 0x31, 0x34,
+	0xf0, 0x08, 0x0b, 0x29, 0xf9, 0x5e, 0x31, 0xec, 0xc0, 0x56, 0x41, 0x2d, 0x8e, 0x41, 0x0b, 0xa0,
+	0xf2, 0x3a, 0xf2, 0x86, 0xec, 0xca, 0x2a, 0xcb, 0x04, 0xbd, 0xc5, 0xad, 0xc0, 0x72, 0x31, 0xc1,
+	0x41, 0x7c, 0xe9, 0x57, 0x0d, 0x01, 0xa1, 0x3a, 0x97, 0xbe, 0xfd, 0x9e, 0x77, 0x85, 0xec, 0x9b,
+	0xea, 0xd8, 0xf8, 0xca, 0xf1, 0xd8, 0x6f, 0x35, 0xde, 0xc5, 0x0a, 0x88, 0xf6, 0x6b, 0x1a, 0xcc,
+	0x95, 0xe3, 0xe1, 0xc4, 0xb3, 0x50, 0x72, 0x82, 0xa2, 0x54, 0x54, 0xa4, 0x47, 0x37, 0xc0, 0xac,
+	0xd7, 0x4c, 0x9e, 0x59, 0x4e, 0x6a, 0x57, 0xb0, 0x5d, 0x34, 0xc9, 0x93, 0xfc, 0x12, 0x2a, 0x57,
+	0x1c, 0xe3, 0xbd, 0x59, 0x2e, 0xc7, 0xa9, 0x7c, 0x2a, 0x45, 0x1f, 0x01, 0x6e, 0xd6, 0xe6, 0x6e,
+	0x52, 0x9a, 0x56, 0x2a, 0xdd, 0xcb, 0x44, 0x2b, 0x29, 0x81, 0x64, 0x8f, 0x05, 0x3c, 0xdb, 0xe3,
+	0xc4, 0x70, 0xe9, 0x1e, 0xa7, 0xfe, 0x33, 0x31, 0xed, 0x59, 0xb2, 0x59, 0xc5, 0xfc, 0x94, 0x6c,
+	0x90, 0xa6, 0xc0, 0x76, 0x51, 0x38, 0x76, 0x7d, 0xf8, 0x07, 0x58, 0x15, 0x7f, 0x4f, 0xa1, 0x07,
+	0xb0, 0xd5, 0x39, 0xd7, 0x8d, 0xd7, 0x67, 0x9d, 0x0b, 0xb3, 0xd5, 0x69, 0xe8, 0xe6, 0xc9, 0x37,
+	0xfa, 0xc9, 0x1b, 0xbd, 0x21, 0xdf, 0x43, 0x8f, 0x40, 0xc9, 0xb3, 0x7a, 0xf5, 0xfe, 0xc0, 0xa8,
+	0xf7, 0x9b, 0xed, 0x53, 0x59, 0x42, 0x2a, 0x6c, 0xe7, 0xb9, 0x17, 0x46, 0xbd, 0xdb, 0xa5, 0xbc,
+	0xb9, 0x49, 0xa3, 0x17, 0xcd, 0x86, 0xde, 0xd6, 0x1b, 0xf2, 0xfc, 0xe1, 0x3f, 0x25, 0x58, 0x15,
+	0x7f, 0x57, 0xa0, 0x5d, 0x78, 0x70, 0xdc, 0x3c, 0x35, 0x3b, 0x5d, 0x9d, 0x9a, 0xee, 0xb4, 0xcd,
+	0x41, 0xbb, 0xd7, 0xd5, 0x4f, 0x9a, 0xaf, 0x9b, 0x2c, 0x88, 0x2d, 0xd8, 0xc8, 0xb3, 0xeb, 0x8d,
+	0x46, 0xec, 0x3d, 0x0f, 0xf7, 0x06, 0xc7, 0x7d, 0xa3, 0x7e, 0xd2, 0x97, 0xe7, 0x26, 0x79, 0xad,
+	0xc1, 0x59, 0xbf, 0xd9, 0x3d, 0x7b, 0x2b, 0xcf, 0x23, 0x05, 0x36, 0xf3, 0xbc, 0x46, 0xf3, 0xbc,
+	0xd9, 0xd0, 0xe5, 0x85, 0x49, 0x4e, 0xab, 0xd3, 0x18, 0x9c, 0x75, 0xe4, 0x45, 0xb4, 0x03, 0xf7,
+	0xf3, 0x9c, 0x6e, 0xe7, 0x42, 0x37, 0xe4, 0x25, 0xba, 0xcc, 0x09, 0x15, 0xca, 0x94, 0x97, 0x0f,
+	0xff, 0x2a, 0x41, 0x35, 0xbd, 0xa2, 0xa9, 0x60, 0xfd, 0xf4, 0xd4, 0xd0, 0x4f, 0xeb, 0x7d, 0xbd,
+	0xb0, 0xbe, 0xfb, 0xb0, 0x9e, 0xb1, 0x4e, 0x3a, 0x83, 0x76, 0x5f, 0x96, 0xd0, 0x06, 0xac, 0x65,
+	0x60, 0x6f, 0xd0, 0x92, 0xe7, 0x10, 0x82, 0x5a, 0x06, 0xb5, 0xf4, 0x7a, 0x5b, 0x9e, 0xcf, 0x8b,
+	0xb5, 0x9a, 0x6d, 0x79, 0xa1, 0x00, 0xd5, 0x7f, 0x23, 0x2f, 0xe6, 0x35, 0xf5, 0x8b, 0x56, 0x5d,
+	0x5e, 0x3a, 0xbc, 0x02, 0xc8, 0xde, 0x62, 0x9a, 0xb0, 0x8b, 0x66, 0xbb, 0xd1, 0xb9, 0x30, 0xfb,
+	0x6f, 0xbb, 0xba, 0x79, 0x32, 0x68, 0x0d, 0xce, 0xea, 0xfd, 0xe6, 0xb9, 0x2e, 0xdf, 0xa3, 0xdb,
+	0x23, 0xf2, 0x7a, 0x67, 0xcd, 0x46, 0xb3, 0x7d, 0x9a, 0x46, 0x5a, 0x60, 0xf7, 0x07, 0xad, 0xe3,
+	0x33, 0xca, 0xef, 0x37, 0x5b, 0xba, 0x3c, 0x77, 0xf8, 0x35, 0x54, 0x92, 0x87, 0x16, 0x3d, 0x84,
+	0x1d, 0xbd, 0xd5, 0xec, 0xc7, 0x05, 0xa1, 0x9f, 0xeb, 0xc6, 0x5b, 0xb3, 0xa5, 0xf7, 0x7a, 0xf5,
+	0x53, 0xea, 0x66, 0x07, 0xee, 0x67, 0xcc, 0x4e, 0xdb, 0x3c, 0xf9, 0xa6, 0xde, 0x3e, 0xd5, 0x65,
+	0xe9, 0xe8, 0x5f, 0x35, 0xd8, 0x38, 0x49, 0x4f, 0x46, 0x0f, 0x07, 0x37, 0xce, 0x10, 0xa3, 0xaf,
+	0x60, 0xbe, 0x17, 0xb9, 0x28, 0xd7, 0x5c, 0x64, 0xb3, 0x35, 0x75, 0x67, 0x02, 0xe7, 0x17, 0xdb,
+	0x3d, 0xf4, 0x1e, 0x94, 0x69, 0x03, 0x24, 0xf4, 0x2c, 0xd7, 0x70, 0xcd, 0x9e, 0x50, 0xa9, 0x9f,
+	0x7d, 0x3f, 0xe1, 0xc4, 0xf1, 0x4b, 0x09, 0x7d, 0x0b, 0xb5, 0xfc, 0x0c, 0x09, 0xed, 0x8b, 0x36,
+	0x4a, 0x07, 0x52, 0xaa, 0x36, 0x4b, 0x24, 0x31, 0x7e, 0x20, 0x21, 0x1b, 0x36, 0x26, 0x3a, 0x64,
+	0xf4, 0x49, 0x89, 0xf2, 0x44, 0x77, 0xaf, 0x7e, 0xfa, 0x11, 0x29, 0xc1, 0x4b, 0x1f, 0x56, 0x84,
+	0x21, 0x15, 0x7a, 0x9c, 0xbb, 0xb4, 0x26, 0x66, 0x5d, 0xea, 0x93, 0xa9, 0xfc, 0xcc, 0xe6, 0x4b,
+	0x09, 0x0d, 0x41, 0x2e, 0x36, 0x41, 0xe8, 0xa9, 0xa8, 0x3a, 0xa5, 0x47, 0x55, 0x3f, 0x99, 0x2d,
+	0x94, 0x73, 0xd2, 0x02, 0xc8, 0x26, 0x60, 0x68, 0x37, 0x57, 0x21, 0xc5, 0x31, 0x9a, 0xfa, 0x78,
+	0x1a, 0x3b, 0xad, 0xa3, 0xdf, 0xc1, 0x7a, 0x61, 0xf0, 0x84, 0xb4, 0x42, 0xd5, 0x95, 0x4c, 0xcd,
+	0xd4, 0xa7, 0x33, 0x65, 0x52, 0xeb, 0x6f, 0x40, 0x8e, 0xa7, 0x3a, 0xf5, 0xc0, 0x21, 0xef, 0x5c,
+	0x4c, 0x9c, 0x21, 0x7a, 0x50, 0x18, 0xcd, 0x64, 0x23, 0x27, 0x55, 0x2d, 0x63, 0x15, 0x8c, 0x19,
+	0xd6, 0x2c, 0x63, 0x86, 0x35, 0xd5, 0x98, 0x30, 0xa4, 0xd1, 0xee, 0xa1, 0x33, 0xa8, 0xa6, 0x73,
+	0x0e, 0xf4, 0x68, 0x72, 0x4e, 0x90, 0xcd, 0x62, 0xd4, 0xdd, 0x29, 0x5c, 0xe1, 0x48, 0x9c, 0x42,
+	0x25, 0xe9, 0x07, 0xd1, 0xc3, 0x5c, 0x7b, 0x9e, 0xef, 0x3b, 0xd5, 0x47, 0xe5, 0xcc, 0x34, 0xac,
+	0x73, 0x58, 0xcb, 0x35, 0x85, 0x68, 0x2f, 0x57, 0xd4, 0x25, 0x7d, 0xa4, 0xba, 0x3f, 0x43, 0x42,
+	0xb4, 0x9b, 0x6b, 0xd3, 0xf2, 0x76, 0xcb, 0x1a, 0x3f, 0x75, 0x7f, 0x86, 0x84, 0x68, 0x37, 0xd7,
+	0x7a, 0xe5, 0xed, 0x96, 0x35, 0x73, 0xea, 0xfe, 0x0c, 0x89, 0xd4, 0xee, 0x5b, 0xa8, 0xe5, 0x5b,
+	0x1e, 0x54, 0x50, 0x2b, 0xe9, 0xb0, 0x54, 0x6d, 0x96, 0x48, 0x31, 0x15, 0x09, 0xa7, 0x24, 0x15,
+	0xc5, 0x06, 0x48, 0xdd, 0x9f, 0x21, 0x91, 0x0f, 0x59, 0xec, 0x55, 0x50, 0xc9, 0x4a, 0x3f, 0x12,
+	0x72, 0x59, 0xab, 0xa3, 0xdd, 0x43, 0x0d, 0x58, 0xe6, 0x03, 0x26, 0x94, 0xab, 0xea, 0xfc, 0xdc,
+	0x4a, 0x7d, 0x58, 0xca, 0x4b, 0xad, 0xfc, 0x0a, 0xaa, 0xe9, 0xc8, 0x27, 0x5f, 0xf2, 0xc5, 0x69,
+	0x92, 0xba, 0x3b, 0x85, 0x2b, 0xda, 0xea, 0x06, 0xf8, 0xe6, 0x07, 0xb1, 0xd5, 0x01, 0xc8, 0xe6,
+	0x3b, 0xf9, 0x1b, 0x6d, 0x62, 0x60, 0xa4, 0x3e, 0x9e, 0xc6, 0xce, 0x4e, 0xe3, 0x71, 0xed, 0xb7,
+	0xab, 0xe2, 0xdf, 0x59, 0x97, 0x4b, 0xec, 0x4f, 0xac, 0x2f, 0xfe, 0x33, 0x00, 0xdf, 0x05, 0x83,
+	0x61, 0xe5, 0x1a, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CalculatorServiceClient is the client API for CalculatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CalculatorServiceClient interface {
+	// Unary
+	Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error)
+	// Server Streaming
+	PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalculatorService_PrimeNumberDecompositionClient, error)
+	// Client Streaming
+	ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeAverageClient, error)
+	ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeStatisticsClient, error)
+	// BiDi Streaming
+	FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaximumClient, error)
+	RunningAggregate(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_RunningAggregateClient, error)
+	// Error Handing
+	SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+	// Dead Line
+	SumWithDeadLine(ctx context.Context, in *SumWithDeadLineRequest, opts ...grpc.CallOption) (*SumWithDeadLineResponse, error)
+	// Arbitrary-precision integers and rationals
+	BigIntArithmetic(ctx context.Context, in *BigIntRequest, opts ...grpc.CallOption) (*BigIntResponse, error)
+	BigRatArithmetic(ctx context.Context, in *BigRatRequest, opts ...grpc.CallOption) (*BigRatResponse, error)
+	// Prime factorization of big integers with exponents and progress
+	Factorize(ctx context.Context, in *FactorizeRequest, opts ...grpc.CallOption) (CalculatorService_FactorizeClient, error)
+	// Expressions
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
+	ListVariables(ctx context.Context, in *ListVariablesRequest, opts ...grpc.CallOption) (*ListVariablesResponse, error)
+	DeleteSession(ctx context.Context, in *DeleteSessionRequest, opts ...grpc.CallOption) (*DeleteSessionResponse, error)
+	DefineFunction(ctx context.Context, in *DefineFunctionRequest, opts ...grpc.CallOption) (*DefineFunctionResponse, error)
+	ListFunctions(ctx context.Context, in *ListFunctionsRequest, opts ...grpc.CallOption) (*ListFunctionsResponse, error)
+	DeleteFunction(ctx context.Context, in *DeleteFunctionRequest, opts ...grpc.CallOption) (*DeleteFunctionResponse, error)
+	// Primality testing and prime generation
+	IsPrime(ctx context.Context, in *IsPrimeRequest, opts ...grpc.CallOption) (*IsPrimeResponse, error)
+	NextPrime(ctx context.Context, in *NextPrimeRequest, opts ...grpc.CallOption) (*NextPrimeResponse, error)
+	PrevPrime(ctx context.Context, in *NextPrimeRequest, opts ...grpc.CallOption) (*NextPrimeResponse, error)
+	ListPrimes(ctx context.Context, in *ListPrimesRequest, opts ...grpc.CallOption) (CalculatorService_ListPrimesClient, error)
+}
+
+type calculatorServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCalculatorServiceClient(cc *grpc.ClientConn) CalculatorServiceClient {
+	return &calculatorServiceClient{cc}
+}
+
+func (c *calculatorServiceClient) Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error) {
+	out := new(SumResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Sum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalculatorService_PrimeNumberDecompositionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[0], "/calculator.CalculatorService/PrimeNumberDecomposition", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServicePrimeNumberDecompositionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CalculatorService_PrimeNumberDecompositionClient interface {
+	Recv() (*PrimeNumberDecompositionResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServicePrimeNumberDecompositionClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServicePrimeNumberDecompositionClient) Recv() (*PrimeNumberDecompositionResponse, error) {
+	m := new(PrimeNumberDecompositionResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeAverageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[1], "/calculator.CalculatorService/ComputeAverage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceComputeAverageClient{stream}
+	return x, nil
+}
+
+type CalculatorService_ComputeAverageClient interface {
+	Send(*ComputeAverageRequest) error
+	CloseAndRecv() (*ComputeAverageResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceComputeAverageClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceComputeAverageClient) Send(m *ComputeAverageRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeAverageClient) CloseAndRecv() (*ComputeAverageResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ComputeAverageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeStatisticsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[2], "/calculator.CalculatorService/ComputeStatistics", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceComputeStatisticsClient{stream}
+	return x, nil
+}
+
+type CalculatorService_ComputeStatisticsClient interface {
+	Send(*ComputeStatisticsRequest) error
+	CloseAndRecv() (*ComputeStatisticsResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceComputeStatisticsClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceComputeStatisticsClient) Send(m *ComputeStatisticsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeStatisticsClient) CloseAndRecv() (*ComputeStatisticsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ComputeStatisticsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaximumClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[3], "/calculator.CalculatorService/FindMaximum", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceFindMaximumClient{stream}
+	return x, nil
+}
+
+type CalculatorService_FindMaximumClient interface {
+	Send(*FindMaximumRequest) error
+	Recv() (*FindMaximumResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceFindMaximumClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceFindMaximumClient) Send(m *FindMaximumRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceFindMaximumClient) Recv() (*FindMaximumResponse, error) {
+	m := new(FindMaximumResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) RunningAggregate(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_RunningAggregateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[4], "/calculator.CalculatorService/RunningAggregate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceRunningAggregateClient{stream}
+	return x, nil
+}
+
+type CalculatorService_RunningAggregateClient interface {
+	Send(*RunningAggregateRequest) error
+	Recv() (*RunningAggregateResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceRunningAggregateClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceRunningAggregateClient) Send(m *RunningAggregateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceRunningAggregateClient) Recv() (*RunningAggregateResponse, error) {
+	m := new(RunningAggregateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
+	out := new(SquareRootResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SquareRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) SumWithDeadLine(ctx context.Context, in *SumWithDeadLineRequest, opts ...grpc.CallOption) (*SumWithDeadLineResponse, error) {
+	out := new(SumWithDeadLineResponse)
 	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SumWithDeadLine", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -717,6 +2988,178 @@ func (c *calculatorServiceClient) SumWithDeadLine(ctx context.Context, in *SumWi
 	return out, nil
 }
 
+func (c *calculatorServiceClient) BigIntArithmetic(ctx context.Context, in *BigIntRequest, opts ...grpc.CallOption) (*BigIntResponse, error) {
+	out := new(BigIntResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/BigIntArithmetic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) BigRatArithmetic(ctx context.Context, in *BigRatRequest, opts ...grpc.CallOption) (*BigRatResponse, error) {
+	out := new(BigRatResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/BigRatArithmetic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Factorize(ctx context.Context, in *FactorizeRequest, opts ...grpc.CallOption) (CalculatorService_FactorizeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[5], "/calculator.CalculatorService/Factorize", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceFactorizeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CalculatorService_FactorizeClient interface {
+	Recv() (*FactorizeResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceFactorizeClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceFactorizeClient) Recv() (*FactorizeResponse, error) {
+	m := new(FactorizeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Evaluate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error) {
+	out := new(CreateSessionResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/CreateSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ListVariables(ctx context.Context, in *ListVariablesRequest, opts ...grpc.CallOption) (*ListVariablesResponse, error) {
+	out := new(ListVariablesResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/ListVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DeleteSession(ctx context.Context, in *DeleteSessionRequest, opts ...grpc.CallOption) (*DeleteSessionResponse, error) {
+	out := new(DeleteSessionResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DeleteSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DefineFunction(ctx context.Context, in *DefineFunctionRequest, opts ...grpc.CallOption) (*DefineFunctionResponse, error) {
+	out := new(DefineFunctionResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DefineFunction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ListFunctions(ctx context.Context, in *ListFunctionsRequest, opts ...grpc.CallOption) (*ListFunctionsResponse, error) {
+	out := new(ListFunctionsResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/ListFunctions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DeleteFunction(ctx context.Context, in *DeleteFunctionRequest, opts ...grpc.CallOption) (*DeleteFunctionResponse, error) {
+	out := new(DeleteFunctionResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DeleteFunction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) IsPrime(ctx context.Context, in *IsPrimeRequest, opts ...grpc.CallOption) (*IsPrimeResponse, error) {
+	out := new(IsPrimeResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/IsPrime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) NextPrime(ctx context.Context, in *NextPrimeRequest, opts ...grpc.CallOption) (*NextPrimeResponse, error) {
+	out := new(NextPrimeResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/NextPrime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) PrevPrime(ctx context.Context, in *NextPrimeRequest, opts ...grpc.CallOption) (*NextPrimeResponse, error) {
+	out := new(NextPrimeResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/PrevPrime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ListPrimes(ctx context.Context, in *ListPrimesRequest, opts ...grpc.CallOption) (CalculatorService_ListPrimesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[6], "/calculator.CalculatorService/ListPrimes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceListPrimesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CalculatorService_ListPrimesClient interface {
+	Recv() (*ListPrimesResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceListPrimesClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceListPrimesClient) Recv() (*ListPrimesResponse, error) {
+	m := new(ListPrimesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // CalculatorServiceServer is the server API for CalculatorService service.
 type CalculatorServiceServer interface {
 	// Unary
@@ -725,12 +3168,32 @@ type CalculatorServiceServer interface {
 	PrimeNumberDecomposition(*PrimeNumberDecompositionRequest, CalculatorService_PrimeNumberDecompositionServer) error
 	// Client Streaming
 	ComputeAverage(CalculatorService_ComputeAverageServer) error
+	ComputeStatistics(CalculatorService_ComputeStatisticsServer) error
 	// BiDi Streaming
 	FindMaximum(CalculatorService_FindMaximumServer) error
+	RunningAggregate(CalculatorService_RunningAggregateServer) error
 	// Error Handing
 	SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error)
 	// Dead Line
 	SumWithDeadLine(context.Context, *SumWithDeadLineRequest) (*SumWithDeadLineResponse, error)
+	// Arbitrary-precision integers and rationals
+	BigIntArithmetic(context.Context, *BigIntRequest) (*BigIntResponse, error)
+	BigRatArithmetic(context.Context, *BigRatRequest) (*BigRatResponse, error)
+	// Prime factorization of big integers with exponents and progress
+	Factorize(*FactorizeRequest, CalculatorService_FactorizeServer) error
+	// Expressions
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+	CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
+	ListVariables(context.Context, *ListVariablesRequest) (*ListVariablesResponse, error)
+	DeleteSession(context.Context, *DeleteSessionRequest) (*DeleteSessionResponse, error)
+	DefineFunction(context.Context, *DefineFunctionRequest) (*DefineFunctionResponse, error)
+	ListFunctions(context.Context, *ListFunctionsRequest) (*ListFunctionsResponse, error)
+	DeleteFunction(context.Context, *DeleteFunctionRequest) (*DeleteFunctionResponse, error)
+	// Primality testing and prime generation
+	IsPrime(context.Context, *IsPrimeRequest) (*IsPrimeResponse, error)
+	NextPrime(context.Context, *NextPrimeRequest) (*NextPrimeResponse, error)
+	PrevPrime(context.Context, *NextPrimeRequest) (*NextPrimeResponse, error)
+	ListPrimes(*ListPrimesRequest, CalculatorService_ListPrimesServer) error
 }
 
 // UnimplementedCalculatorServiceServer can be embedded to have forward compatible implementations.
@@ -746,15 +3209,63 @@ func (*UnimplementedCalculatorServiceServer) PrimeNumberDecomposition(req *Prime
 func (*UnimplementedCalculatorServiceServer) ComputeAverage(srv CalculatorService_ComputeAverageServer) error {
 	return status.Errorf(codes.Unimplemented, "method ComputeAverage not implemented")
 }
+func (*UnimplementedCalculatorServiceServer) ComputeStatistics(srv CalculatorService_ComputeStatisticsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ComputeStatistics not implemented")
+}
 func (*UnimplementedCalculatorServiceServer) FindMaximum(srv CalculatorService_FindMaximumServer) error {
 	return status.Errorf(codes.Unimplemented, "method FindMaximum not implemented")
 }
+func (*UnimplementedCalculatorServiceServer) RunningAggregate(srv CalculatorService_RunningAggregateServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunningAggregate not implemented")
+}
 func (*UnimplementedCalculatorServiceServer) SquareRoot(ctx context.Context, req *SquareRootRequest) (*SquareRootResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SquareRoot not implemented")
 }
 func (*UnimplementedCalculatorServiceServer) SumWithDeadLine(ctx context.Context, req *SumWithDeadLineRequest) (*SumWithDeadLineResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SumWithDeadLine not implemented")
 }
+func (*UnimplementedCalculatorServiceServer) BigIntArithmetic(ctx context.Context, req *BigIntRequest) (*BigIntResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigIntArithmetic not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) BigRatArithmetic(ctx context.Context, req *BigRatRequest) (*BigRatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigRatArithmetic not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Factorize(req *FactorizeRequest, srv CalculatorService_FactorizeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Factorize not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Evaluate(ctx context.Context, req *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) CreateSession(ctx context.Context, req *CreateSessionRequest) (*CreateSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) ListVariables(ctx context.Context, req *ListVariablesRequest) (*ListVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVariables not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) DeleteSession(ctx context.Context, req *DeleteSessionRequest) (*DeleteSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSession not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) DefineFunction(ctx context.Context, req *DefineFunctionRequest) (*DefineFunctionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DefineFunction not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) ListFunctions(ctx context.Context, req *ListFunctionsRequest) (*ListFunctionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFunctions not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) DeleteFunction(ctx context.Context, req *DeleteFunctionRequest) (*DeleteFunctionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFunction not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) IsPrime(ctx context.Context, req *IsPrimeRequest) (*IsPrimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsPrime not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) NextPrime(ctx context.Context, req *NextPrimeRequest) (*NextPrimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NextPrime not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) PrevPrime(ctx context.Context, req *NextPrimeRequest) (*NextPrimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrevPrime not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) ListPrimes(req *ListPrimesRequest, srv CalculatorService_ListPrimesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPrimes not implemented")
+}
 
 func RegisterCalculatorServiceServer(s *grpc.Server, srv CalculatorServiceServer) {
 	s.RegisterService(&_CalculatorService_serviceDesc, srv)
@@ -825,6 +3336,32 @@ func (x *calculatorServiceComputeAverageServer) Recv() (*ComputeAverageRequest,
 	return m, nil
 }
 
+func _CalculatorService_ComputeStatistics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).ComputeStatistics(&calculatorServiceComputeStatisticsServer{stream})
+}
+
+type CalculatorService_ComputeStatisticsServer interface {
+	SendAndClose(*ComputeStatisticsResponse) error
+	Recv() (*ComputeStatisticsRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceComputeStatisticsServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceComputeStatisticsServer) SendAndClose(m *ComputeStatisticsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeStatisticsServer) Recv() (*ComputeStatisticsRequest, error) {
+	m := new(ComputeStatisticsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _CalculatorService_FindMaximum_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(CalculatorServiceServer).FindMaximum(&calculatorServiceFindMaximumServer{stream})
 }
@@ -851,6 +3388,32 @@ func (x *calculatorServiceFindMaximumServer) Recv() (*FindMaximumRequest, error)
 	return m, nil
 }
 
+func _CalculatorService_RunningAggregate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).RunningAggregate(&calculatorServiceRunningAggregateServer{stream})
+}
+
+type CalculatorService_RunningAggregateServer interface {
+	Send(*RunningAggregateResponse) error
+	Recv() (*RunningAggregateRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceRunningAggregateServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceRunningAggregateServer) Send(m *RunningAggregateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceRunningAggregateServer) Recv() (*RunningAggregateRequest, error) {
+	m := new(RunningAggregateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _CalculatorService_SquareRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SquareRootRequest)
 	if err := dec(in); err != nil {
@@ -887,6 +3450,264 @@ func _CalculatorService_SumWithDeadLine_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CalculatorService_BigIntArithmetic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigIntRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).BigIntArithmetic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/BigIntArithmetic",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).BigIntArithmetic(ctx, req.(*BigIntRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_BigRatArithmetic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigRatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).BigRatArithmetic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/BigRatArithmetic",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).BigRatArithmetic(ctx, req.(*BigRatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Factorize_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FactorizeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServiceServer).Factorize(m, &calculatorServiceFactorizeServer{stream})
+}
+
+type CalculatorService_FactorizeServer interface {
+	Send(*FactorizeResponse) error
+	grpc.ServerStream
+}
+
+type calculatorServiceFactorizeServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceFactorizeServer) Send(m *FactorizeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CalculatorService_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Evaluate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/CreateSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ListVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ListVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/ListVariables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ListVariables(ctx, req.(*ListVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DeleteSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DeleteSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DeleteSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DeleteSession(ctx, req.(*DeleteSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DefineFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DefineFunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DefineFunction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DefineFunction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DefineFunction(ctx, req.(*DefineFunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ListFunctions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFunctionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ListFunctions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/ListFunctions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ListFunctions(ctx, req.(*ListFunctionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DeleteFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DeleteFunction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DeleteFunction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DeleteFunction(ctx, req.(*DeleteFunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_IsPrime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsPrimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).IsPrime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/IsPrime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).IsPrime(ctx, req.(*IsPrimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_NextPrime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextPrimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).NextPrime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/NextPrime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).NextPrime(ctx, req.(*NextPrimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_PrevPrime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextPrimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).PrevPrime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/PrevPrime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).PrevPrime(ctx, req.(*NextPrimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ListPrimes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPrimesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServiceServer).ListPrimes(m, &calculatorServiceListPrimesServer{stream})
+}
+
+type CalculatorService_ListPrimesServer interface {
+	Send(*ListPrimesResponse) error
+	grpc.ServerStream
+}
+
+type calculatorServiceListPrimesServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceListPrimesServer) Send(m *ListPrimesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _CalculatorService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "calculator.CalculatorService",
 	HandlerType: (*CalculatorServiceServer)(nil),
@@ -903,6 +3724,54 @@ var _CalculatorService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SumWithDeadLine",
 			Handler:    _CalculatorService_SumWithDeadLine_Handler,
 		},
+		{
+			MethodName: "BigIntArithmetic",
+			Handler:    _CalculatorService_BigIntArithmetic_Handler,
+		},
+		{
+			MethodName: "BigRatArithmetic",
+			Handler:    _CalculatorService_BigRatArithmetic_Handler,
+		},
+		{
+			MethodName: "Evaluate",
+			Handler:    _CalculatorService_Evaluate_Handler,
+		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _CalculatorService_CreateSession_Handler,
+		},
+		{
+			MethodName: "ListVariables",
+			Handler:    _CalculatorService_ListVariables_Handler,
+		},
+		{
+			MethodName: "DeleteSession",
+			Handler:    _CalculatorService_DeleteSession_Handler,
+		},
+		{
+			MethodName: "DefineFunction",
+			Handler:    _CalculatorService_DefineFunction_Handler,
+		},
+		{
+			MethodName: "ListFunctions",
+			Handler:    _CalculatorService_ListFunctions_Handler,
+		},
+		{
+			MethodName: "DeleteFunction",
+			Handler:    _CalculatorService_DeleteFunction_Handler,
+		},
+		{
+			MethodName: "IsPrime",
+			Handler:    _CalculatorService_IsPrime_Handler,
+		},
+		{
+			MethodName: "NextPrime",
+			Handler:    _CalculatorService_NextPrime_Handler,
+		},
+		{
+			MethodName: "PrevPrime",
+			Handler:    _CalculatorService_PrevPrime_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -915,12 +3784,33 @@ var _CalculatorService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _CalculatorService_ComputeAverage_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ComputeStatistics",
+			Handler:       _CalculatorService_ComputeStatistics_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "FindMaximum",
 			Handler:       _CalculatorService_FindMaximum_Handler,
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "RunningAggregate",
+			Handler:       _CalculatorService_RunningAggregate_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Factorize",
+			Handler:       _CalculatorService_Factorize_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListPrimes",
+			Handler:       _CalculatorService_ListPrimes_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "calculatorpb/calculator.proto",
 }